@@ -0,0 +1,307 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/pkg/errors"
+)
+
+const entityAttrsPath string = "attrs"
+
+// UpdateResult lists the outcome of an operation on the Attributes of an Entity
+type UpdateResult struct {
+	Updated    []string            `json:"updated"`
+	NotUpdated []NotUpdatedDetails `json:"notUpdated"`
+}
+
+type NotUpdatedDetails struct {
+	AttributeName string `json:"attributeName"`
+	Reason        string `json:"reason"`
+}
+
+type appendAttributesOptions struct {
+	noOverwrite bool
+}
+
+type AppendOptionFunc func(*appendAttributesOptions) error
+
+// AppendSetNoOverwrite keeps the existing Attributes untouched, only new ones are appended
+var AppendSetNoOverwrite AppendOptionFunc = func(o *appendAttributesOptions) error {
+	o.noOverwrite = true
+	return nil
+}
+
+// UpdateEntityAttributes replaces the existing Attributes of the Entity with the ones of the fragment.
+// Attributes missing in the Entity are reported as not updated.
+func (client *NgsiLdClient) UpdateEntityAttributes(ctx context.Context, ldCtx *ldcontext.LdContext, id string, attrs *model.EntityFragment) (*UpdateResult, error) {
+	attrsURL := strings.Join([]string{client.url, entitiesEndpoint, url.PathEscape(id), entityAttrsPath}, "/")
+	return client.sendEntityAttributes(ctx, ldCtx, http.MethodPatch, attrsURL, id, attrs)
+}
+
+// AppendEntityAttributes adds the Attributes of the fragment to the Entity,
+// overwriting the existing ones unless AppendSetNoOverwrite is given
+func (client *NgsiLdClient) AppendEntityAttributes(ctx context.Context, ldCtx *ldcontext.LdContext, id string, attrs *model.EntityFragment, opts ...AppendOptionFunc) (*UpdateResult, error) {
+	requestOptions := &appendAttributesOptions{}
+	for _, o := range opts {
+		err := o(requestOptions)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidAppendOptions, err.Error())
+		}
+	}
+
+	attrsURL := strings.Join([]string{client.url, entitiesEndpoint, url.PathEscape(id), entityAttrsPath}, "/")
+	if requestOptions.noOverwrite {
+		attrsURL += "?options=noOverwrite"
+	}
+	return client.sendEntityAttributes(ctx, ldCtx, http.MethodPost, attrsURL, id, attrs)
+}
+
+func (client *NgsiLdClient) sendEntityAttributes(ctx context.Context, ldCtx *ldcontext.LdContext, method, attrsURL, id string, attrs *model.EntityFragment) (*UpdateResult, error) {
+	// Set default context whenever missing
+	if ldCtx == nil {
+		ldCtx = &ldcontext.DefaultContext
+	}
+
+	if id == "" {
+		return nil, model.ErrEntityMissingID
+	}
+	if attrs == nil {
+		return nil, errors.Wrap(model.ErrEntityFragmentEmpty, "invalid Attributes")
+	}
+	err := attrs.Validate(true)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Attributes")
+	}
+
+	attrsRequest, bodyHeaders, err := client.addContext(attrs, ldCtx)
+	if err != nil {
+		return nil, err
+	}
+	attrsRequestBody, err := json.Marshal(&attrsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := client.newRequest(
+		ctx,
+		method,
+		attrsURL,
+		bytes.NewBuffer(attrsRequestBody),
+		bodyHeaders...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't update Attributes")
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return &UpdateResult{Updated: attrs.Names()}, nil
+	case http.StatusMultiStatus:
+		bodyBytes, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't read update result")
+		}
+		result := UpdateResult{}
+		err = json.Unmarshal(bodyBytes, &result)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't decode update result: %s", string(bodyBytes))
+		}
+		return &result, nil
+	}
+
+	return nil, errors.Wrapf(responseError(resp), "ID: %s", id)
+}
+
+type attributeOptions struct {
+	datasetID string
+	deleteAll bool
+}
+
+type AttributeOptionFunc func(*attributeOptions) error
+
+// AttributeSetDatasetID targets the instance of the Attribute having the datasetId
+func AttributeSetDatasetID(datasetID string) AttributeOptionFunc {
+	return func(o *attributeOptions) error {
+		if datasetID == "" {
+			return errors.New("empty datasetId")
+		}
+		o.datasetID = datasetID
+		return nil
+	}
+}
+
+// AttributeSetDeleteAll targets every instance of the Attribute, whatever their datasetId.
+// It only applies to deletion.
+var AttributeSetDeleteAll AttributeOptionFunc = func(o *attributeOptions) error {
+	o.deleteAll = true
+	return nil
+}
+
+func newAttributeOptions(opts []AttributeOptionFunc) (*attributeOptions, error) {
+	requestOptions := &attributeOptions{}
+	for _, o := range opts {
+		err := o(requestOptions)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidAttributeOptions, err.Error())
+		}
+	}
+	if requestOptions.deleteAll && requestOptions.datasetID != "" {
+		return nil, errors.Wrap(ErrInvalidAttributeOptions, "datasetId and deleteAll are mutually exclusive")
+	}
+	return requestOptions, nil
+}
+
+// PartialAttributeUpdate modifies the Attribute of the Entity with the values of attr.
+// The instance to modify is the default one, or the one selected with AttributeSetDatasetID.
+func (client *NgsiLdClient) PartialAttributeUpdate(ctx context.Context, ldCtx *ldcontext.LdContext, id, attrID string, attr model.Attribute, opts ...AttributeOptionFunc) error {
+	// Set default context whenever missing
+	if ldCtx == nil {
+		ldCtx = &ldcontext.DefaultContext
+	}
+
+	requestOptions, err := newAttributeOptions(opts)
+	if err != nil {
+		return err
+	}
+	if requestOptions.deleteAll {
+		return errors.Wrap(ErrInvalidAttributeOptions, "deleteAll only applies to deletion")
+	}
+
+	if id == "" {
+		return model.ErrEntityMissingID
+	}
+	if attrID == "" {
+		return errors.Wrap(ErrInvalidAttributeOptions, "empty attribute name")
+	}
+	if nilAttribute(attr) {
+		return errors.Wrap(ErrInvalidAttributeOptions, "missing Attribute")
+	}
+	err = attr.Validate(true)
+	if err != nil {
+		return errors.Wrap(err, "invalid Attribute")
+	}
+
+	attrRequest, bodyHeaders, err := client.addContext(attr, ldCtx)
+	if err != nil {
+		return err
+	}
+	if requestOptions.datasetID != "" {
+		datasetID, err := json.Marshal(requestOptions.datasetID)
+		if err != nil {
+			return err
+		}
+		if current, ok := attrRequest["datasetId"]; ok && !bytes.Equal(current, datasetID) {
+			return errors.Wrap(ErrInvalidAttributeOptions, "datasetId differs from the one of the Attribute")
+		}
+		attrRequest["datasetId"] = datasetID
+	}
+	attrRequestBody, err := json.Marshal(&attrRequest)
+	if err != nil {
+		return err
+	}
+
+	attrURL := strings.Join([]string{client.url, entitiesEndpoint, url.PathEscape(id), entityAttrsPath, url.PathEscape(attrID)}, "/")
+	req, err := client.newRequest(
+		ctx,
+		http.MethodPatch,
+		attrURL,
+		bytes.NewBuffer(attrRequestBody),
+		bodyHeaders...,
+	)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "can't update Attribute")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return errors.Wrapf(responseError(resp), "ID: %s, Attribute: %s", id, attrID)
+}
+
+// DeleteEntityAttribute removes the Attribute from the Entity.
+// The instance to remove is the default one, unless AttributeSetDatasetID or
+// AttributeSetDeleteAll are given.
+func (client *NgsiLdClient) DeleteEntityAttribute(ctx context.Context, ldCtx *ldcontext.LdContext, id, attrID string, opts ...AttributeOptionFunc) error {
+	requestOptions, err := newAttributeOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	if id == "" {
+		return model.ErrEntityMissingID
+	}
+	if attrID == "" {
+		return errors.Wrap(ErrInvalidAttributeOptions, "empty attribute name")
+	}
+
+	headers := []requestHeader{}
+	link, err := contextLink(ldCtx)
+	if err != nil {
+		return err
+	}
+	if link != nil {
+		headers = append(headers, *link)
+	}
+
+	attrURL := strings.Join([]string{client.url, entitiesEndpoint, url.PathEscape(id), entityAttrsPath, url.PathEscape(attrID)}, "/")
+	req, err := client.newRequest(
+		ctx,
+		http.MethodDelete,
+		attrURL,
+		nil,
+		headers...,
+	)
+	if err != nil {
+		return err
+	}
+
+	q := req.URL.Query()
+	if requestOptions.datasetID != "" {
+		q.Set("datasetId", requestOptions.datasetID)
+	}
+	if requestOptions.deleteAll {
+		q.Set("deleteAll", "true")
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "can't delete Attribute")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return errors.Wrapf(responseError(resp), "ID: %s, Attribute: %s", id, attrID)
+}
+
+// nilAttribute tells if the Attribute is missing, nil pointers included
+func nilAttribute(attr model.Attribute) bool {
+	if attr == nil {
+		return true
+	}
+	rv := reflect.ValueOf(attr)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
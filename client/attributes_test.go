@@ -0,0 +1,334 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/philiphil/geojson"
+	"github.com/phoops/ngsi-gold/client"
+	"github.com/phoops/ngsi-gold/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateAttributesSuccess(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Assertion on the received request
+				assert.Equal(t, http.MethodPatch, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/entities/urn:room:1/attrs", r.URL.Path)
+				assert.Equal(t, "application/ld+json", r.Header.Get("Content-Type"))
+
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				body := map[string]any{}
+				assert.NoError(t, json.Unmarshal(b, &body))
+				assert.Contains(t, body, "@context")
+				assert.Contains(t, body, "temperature")
+				assert.Contains(t, body, "wall")
+				assert.Contains(t, body, "destination")
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	result, err := cli.UpdateEntityAttributes(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		&model.EntityFragment{
+			Properties:    model.Properties{"temperature": {{Value: 22.5}}},
+			Relationships: model.Relationships{"wall": {{Object: "urn:wall:1"}}},
+			GeoProperties: model.GeoProperties{"destination": {{Value: geojson.NewPointGeometry([]float64{11.25, 43.77})}}},
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"destination", "temperature", "wall"}, result.Updated)
+	assert.Empty(t, result.NotUpdated)
+}
+
+func TestUpdateAttributesMultiStatus(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusMultiStatus)
+				_, err := w.Write([]byte(`{
+          "updated": ["temperature"],
+          "notUpdated": [{"attributeName": "humidity", "reason": "attribute not found"}]
+        }`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	result, err := cli.UpdateEntityAttributes(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		&model.EntityFragment{
+			Properties: model.Properties{
+				"temperature": {{Value: 22.5}},
+				"humidity":    {{Value: 40}},
+			},
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"temperature"}, result.Updated)
+	assert.Equal(t, []client.NotUpdatedDetails{{AttributeName: "humidity", Reason: "attribute not found"}}, result.NotUpdated)
+}
+
+func TestAppendAttributesNoOverwrite(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/entities/urn:room:1/attrs", r.URL.Path)
+				assert.Equal(t, "noOverwrite", r.URL.Query().Get("options"))
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	result, err := cli.AppendEntityAttributes(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		&model.EntityFragment{
+			Properties: model.Properties{"temperature": {{Value: 22.5}}},
+		},
+		client.AppendSetNoOverwrite,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"temperature"}, result.Updated)
+}
+
+func TestUpdateAttributesNotFound(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, err := w.Write([]byte(`{"type": "https://uri.etsi.org/ngsi-ld/errors/ResourceNotFound", "title": "Entity not found"}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	_, err = cli.UpdateEntityAttributes(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		&model.EntityFragment{
+			Properties: model.Properties{"temperature": {{Value: 22.5}}},
+		},
+	)
+	assert.ErrorIs(t, err, client.ErrNgsiLdResourceNotFound)
+}
+
+func TestUpdateAttributesValidation(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("unused"),
+	)
+	assert.NoError(t, err)
+
+	_, err = cli.UpdateEntityAttributes(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		&model.EntityFragment{},
+	)
+	assert.ErrorIs(t, err, model.ErrEntityFragmentEmpty)
+	assert.ErrorContains(t, err, "invalid Attributes")
+}
+
+func TestPartialAttributeUpdateSuccess(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Assertion on the received request
+				assert.Equal(t, http.MethodPatch, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/entities/urn:room:1/attrs/temperature", r.URL.Path)
+				assert.Equal(t, "application/ld+json", r.Header.Get("Content-Type"))
+
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				body := map[string]any{}
+				assert.NoError(t, json.Unmarshal(b, &body))
+				assert.Contains(t, body, "@context")
+				assert.Equal(t, 22.5, body["value"])
+				assert.Equal(t, "urn:dataset:sensor-a", body["datasetId"])
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.PartialAttributeUpdate(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		"temperature",
+		&model.Property{Value: 22.5},
+		client.AttributeSetDatasetID("urn:dataset:sensor-a"),
+	)
+	assert.NoError(t, err)
+}
+
+func TestPartialAttributeUpdateInvalidOptions(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("unused"),
+	)
+	assert.NoError(t, err)
+
+	err = cli.PartialAttributeUpdate(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		"temperature",
+		&model.Property{Value: 22.5},
+		client.AttributeSetDeleteAll,
+	)
+	assert.ErrorIs(t, err, client.ErrInvalidAttributeOptions)
+
+	otherDataset := "urn:dataset:sensor-b"
+	err = cli.PartialAttributeUpdate(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		"temperature",
+		&model.Property{Value: 22.5, DatasetID: &otherDataset},
+		client.AttributeSetDatasetID("urn:dataset:sensor-a"),
+	)
+	assert.ErrorIs(t, err, client.ErrInvalidAttributeOptions)
+	err = cli.PartialAttributeUpdate(context.Background(), nil, "urn:room:1", "temperature", nil)
+	assert.ErrorIs(t, err, client.ErrInvalidAttributeOptions)
+
+	err = cli.PartialAttributeUpdate(context.Background(), nil, "urn:room:1", "temperature", (*model.Property)(nil))
+	assert.ErrorIs(t, err, client.ErrInvalidAttributeOptions)
+}
+
+func TestPartialAttributeUpdateNotFound(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, err := w.Write([]byte(`{"type": "https://uri.etsi.org/ngsi-ld/errors/ResourceNotFound", "title": "Attribute not found"}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.PartialAttributeUpdate(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		"temperature",
+		&model.Property{Value: 22.5},
+	)
+	assert.ErrorIs(t, err, client.ErrNgsiLdResourceNotFound)
+}
+
+func TestDeleteAttribute(t *testing.T) {
+	type testCase struct {
+		name      string
+		opts      []client.AttributeOptionFunc
+		datasetID string
+		deleteAll string
+	}
+
+	tests := []testCase{
+		{
+			name: "default instance",
+		},
+		{
+			name:      "dataset instance",
+			opts:      []client.AttributeOptionFunc{client.AttributeSetDatasetID("urn:dataset:sensor-a")},
+			datasetID: "urn:dataset:sensor-a",
+		},
+		{
+			name:      "all instances",
+			opts:      []client.AttributeOptionFunc{client.AttributeSetDeleteAll},
+			deleteAll: "true",
+		},
+	}
+
+	for _, y := range tests {
+		t.Run(y.name, func(t *testing.T) {
+			ts := httptest.NewServer(
+				http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						assert.Equal(t, http.MethodDelete, r.Method)
+						assert.Equal(t, "/ngsi-ld/v1/entities/urn:room:1/attrs/temperature", r.URL.Path)
+						assert.Equal(t, y.datasetID, r.URL.Query().Get("datasetId"))
+						assert.Equal(t, y.deleteAll, r.URL.Query().Get("deleteAll"))
+
+						w.WriteHeader(http.StatusNoContent)
+					}))
+			defer ts.Close()
+
+			cli, err := client.New(
+				client.SetURL(ts.URL),
+			)
+			assert.NoError(t, err)
+
+			err = cli.DeleteEntityAttribute(
+				context.Background(),
+				nil,
+				"urn:room:1",
+				"temperature",
+				y.opts...,
+			)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDeleteAttributeConflictingOptions(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("unused"),
+	)
+	assert.NoError(t, err)
+
+	err = cli.DeleteEntityAttribute(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		"temperature",
+		client.AttributeSetDatasetID("urn:dataset:sensor-a"),
+		client.AttributeSetDeleteAll,
+	)
+	assert.ErrorIs(t, err, client.ErrInvalidAttributeOptions)
+}
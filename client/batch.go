@@ -0,0 +1,294 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/pkg/errors"
+)
+
+const batchCreateEndpoint string = "ngsi-ld/v1/entityOperations/create"
+const batchUpdateEndpoint string = "ngsi-ld/v1/entityOperations/update"
+
+type batchUpdateOptions struct {
+	noOverwrite bool
+}
+
+type UpdateOptionFunc func(*batchUpdateOptions) error
+
+// UpdateSetNoOverwrite keeps the existing Attributes untouched, only new ones are appended
+var UpdateSetNoOverwrite UpdateOptionFunc = func(o *batchUpdateOptions) error {
+	o.noOverwrite = true
+	return nil
+}
+
+// BatchCreateEntities creates the entities, failing for the ones already existing.
+// When only some of them can be created a *BatchError is returned.
+func (client *NgsiLdClient) BatchCreateEntities(ctx context.Context, payload []*EntityWithContext) error {
+	batchRequest, headers, err := client.newBatchRequest(payload, true)
+	if err != nil {
+		return err
+	}
+
+	return client.runBatch(ctx, batchCreateEndpoint, batchRequest, headers, nil)
+}
+
+// BatchUpdateEntities updates the Attributes of existing entities.
+// When only some of them can be updated a *BatchError is returned.
+func (client *NgsiLdClient) BatchUpdateEntities(ctx context.Context, payload []*EntityWithContext, opts ...UpdateOptionFunc) error {
+	requestOptions := &batchUpdateOptions{}
+	for _, o := range opts {
+		err := o(requestOptions)
+		if err != nil {
+			return errors.Wrap(ErrInvalidUpdateOptions, err.Error())
+		}
+	}
+
+	batchRequest, headers, err := client.newBatchRequest(payload, false)
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	if requestOptions.noOverwrite {
+		q.Set("options", "noOverwrite")
+	}
+	return client.runBatch(ctx, batchUpdateEndpoint, batchRequest, headers, q)
+}
+
+// batchItem is an element of the payload of a batch operation
+type batchItem struct {
+	id   string
+	body json.RawMessage
+}
+
+// newBatchRequest validates the entities and adds them their context,
+// returning the headers describing the payload as well.
+// Entities that may be created, by create or upsert, can't hold system attributes.
+func (client *NgsiLdClient) newBatchRequest(payload []*EntityWithContext, creation bool) ([]batchItem, []requestHeader, error) {
+	batchRequest := make([]batchItem, 0, len(payload))
+	headers, err := client.bodyHeaders(&ldcontext.DefaultContext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, x := range payload {
+		ldCtx := x.LdCtx
+		entity := x.Entity
+		// Set default context whenever missing
+		if ldCtx == nil {
+			ldCtx = &ldcontext.DefaultContext
+		}
+
+		// Validate entity before contacting the server
+		var err error
+		if creation {
+			err = entity.ValidateCreation()
+		} else {
+			err = entity.Validate(true)
+		}
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid Entity")
+		}
+		inner, entityHeaders, err := client.addContext(entity, ldCtx)
+		if err != nil {
+			return nil, nil, err
+		}
+		// A single Link header describes the whole payload
+		if i == 0 {
+			headers = entityHeaders
+		} else if !sameHeaders(headers, entityHeaders) {
+			return nil, nil, errors.Wrap(ErrContextNotLinkable, "the entities of a batch operation must share the @context")
+		}
+		body, err := json.Marshal(&inner)
+		if err != nil {
+			return nil, nil, err
+		}
+		batchRequest = append(batchRequest, batchItem{id: entity.ID, body: body})
+	}
+	return batchRequest, headers, nil
+}
+
+func sameHeaders(a, b []requestHeader) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// chunkBatch splits the payload according to the client configuration
+func (client *NgsiLdClient) chunkBatch(items []batchItem) [][]batchItem {
+	chunks := [][]batchItem{}
+	chunk := []batchItem{}
+	chunkBytes := 2 // enclosing brackets
+
+	for _, item := range items {
+		itemBytes := len(item.body) + 1 // separating comma
+		full := client.batchChunkSize > 0 && len(chunk) >= client.batchChunkSize
+		oversized := client.batchChunkBytes > 0 && chunkBytes+itemBytes > client.batchChunkBytes
+		if len(chunk) > 0 && (full || oversized) {
+			chunks = append(chunks, chunk)
+			chunk = []batchItem{}
+			chunkBytes = 2
+		}
+		chunk = append(chunk, item)
+		chunkBytes += itemBytes
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// runBatch sends a batch operation to the endpoint, split in chunks sent concurrently.
+// A batch fitting in one chunk returns the error of its request. Otherwise the results
+// of the chunks are merged, any failure becomes a *BatchError.
+// A failing chunk stops the operation: the chunks not sent yet are dropped, their
+// entities are reported with ErrNgsiBatchNotSent, while the chunks already sent
+// complete. The entities of a failing chunk are reported with its error: when the
+// request was interrupted, e.g. by cancelling ctx, the chunk may have been applied
+// all the same, so their state is unknown.
+func (client *NgsiLdClient) runBatch(ctx context.Context, endpoint string, items []batchItem, headers []requestHeader, q url.Values) error {
+	chunks := client.chunkBatch(items)
+	switch len(chunks) {
+	case 0:
+		return nil
+	case 1:
+		// Nothing else to merge, a failure of the request is returned as it is
+		result, err := client.postBatch(ctx, endpoint, chunks[0], headers, q)
+		if err != nil {
+			return err
+		}
+		if len(result.Errors) > 0 {
+			return &BatchError{Result: result}
+		}
+		return nil
+	}
+
+	results := make([]BatchOperationResult, len(chunks))
+	failures := make([]error, len(chunks))
+	sent := make([]bool, len(chunks))
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < client.batchWorkers && w < len(chunks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := client.postBatch(ctx, endpoint, chunks[i], headers, q)
+				if err != nil {
+					if len(chunks) > 1 {
+						err = errors.Wrapf(err, "batch chunk %d of %d", i+1, len(chunks))
+					}
+					failures[i] = err
+					stopOnce.Do(func() { close(stop) })
+					continue
+				}
+				results[i] = result
+			}
+		}()
+	}
+
+	// In-flight chunks are not interrupted by a failure, only ctx stops them
+feed:
+	for i := range chunks {
+		select {
+		case <-stop:
+			break feed
+		default:
+		}
+		select {
+		case jobs <- i:
+			sent[i] = true
+		case <-stop:
+			break feed
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	merged := BatchOperationResult{}
+	for i, chunk := range chunks {
+		cause := failures[i]
+		if !sent[i] {
+			cause = ErrNgsiBatchNotSent
+			if err := ctx.Err(); err != nil {
+				cause = errors.Wrap(err, ErrNgsiBatchNotSent.Error())
+			}
+		}
+		if cause == nil {
+			merged.Success = append(merged.Success, results[i].Success...)
+			merged.Errors = append(merged.Errors, results[i].Errors...)
+			continue
+		}
+		for _, item := range chunk {
+			merged.Errors = append(merged.Errors, BatchRequestErrorItem{
+				ID:             item.id,
+				ProblemDetails: ProblemDetails{Title: "Batch chunk failed", Detail: cause.Error()},
+				Cause:          cause,
+			})
+		}
+	}
+	if len(merged.Errors) > 0 {
+		return &BatchError{Result: merged}
+	}
+	return nil
+}
+
+// postBatch sends a chunk of a batch operation to the endpoint.
+// Partial failures are reported in the result, not as error.
+func (client *NgsiLdClient) postBatch(ctx context.Context, endpoint string, chunk []batchItem, headers []requestHeader, q url.Values) (BatchOperationResult, error) {
+	bodies := make([][]byte, 0, len(chunk))
+	ids := make([]string, 0, len(chunk))
+	for _, item := range chunk {
+		bodies = append(bodies, item.body)
+		ids = append(ids, item.id)
+	}
+	batchRequestBody := append(append([]byte("["), bytes.Join(bodies, []byte(","))...), ']')
+
+	batchURL := strings.Join([]string{client.url, endpoint}, "/")
+	req, err := client.newRequest(
+		ctx,
+		http.MethodPost,
+		batchURL,
+		bytes.NewBuffer(batchRequestBody),
+		headers...,
+	)
+	if err != nil {
+		return BatchOperationResult{}, err
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return BatchOperationResult{}, errors.Wrap(err, "can't send batch operation")
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return BatchOperationResult{Success: ids}, nil
+	}
+
+	err = batchResponseError(resp)
+	if batchErr, ok := err.(*BatchError); ok {
+		return batchErr.Result, nil
+	}
+	return BatchOperationResult{}, err
+}
@@ -0,0 +1,447 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsi-gold/client"
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/phoops/ngsi-gold/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testBatchPayload() []*client.EntityWithContext {
+	return []*client.EntityWithContext{
+		{Entity: &model.Entity{ID: "urn:sensor:1", Type: "Sensor"}},
+		{Entity: &model.Entity{ID: "urn:sensor:2", Type: "Sensor"}},
+	}
+}
+
+func TestBatchCreateSuccess(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Assertion on the received request
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/entityOperations/create", r.URL.Path)
+				assert.Equal(t, "application/ld+json", r.Header.Get("Content-Type"))
+
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				body := []map[string]any{}
+				assert.NoError(t, json.Unmarshal(b, &body))
+				assert.Len(t, body, 2)
+				assert.Contains(t, body[0], "@context")
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				_, err = w.Write([]byte(`["urn:sensor:1","urn:sensor:2"]`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.BatchCreateEntities(context.Background(), testBatchPayload())
+	assert.NoError(t, err)
+}
+
+func TestBatchCreateExisting(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusMultiStatus)
+				_, err := w.Write([]byte(`{
+          "success": ["urn:sensor:1"],
+          "errors": [
+            {
+              "@id": "urn:sensor:2",
+              "ProblemDetails": {
+                "type": "https://uri.etsi.org/ngsi-ld/errors/AlreadyExists",
+                "title": "Already exists."
+              }
+            }
+          ]
+        }`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.BatchCreateEntities(context.Background(), testBatchPayload())
+	assert.ErrorIs(t, err, client.ErrNgsiMixedResponse)
+
+	var batchErr *client.BatchError
+	assert.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, []string{"urn:sensor:1"}, batchErr.Result.Success)
+	assert.Equal(t, []string{"urn:sensor:2"}, batchErr.Result.FailedIDs())
+}
+
+func TestBatchCreateValidation(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("unused"),
+	)
+	assert.NoError(t, err)
+
+	err = cli.BatchCreateEntities(context.Background(), []*client.EntityWithContext{
+		{Entity: &model.Entity{ID: "urn:sensor:1"}},
+	})
+	assert.ErrorIs(t, err, model.ErrEntityMissingType)
+	assert.ErrorContains(t, err, "invalid Entity")
+}
+
+func TestBatchUpdate(t *testing.T) {
+	type testCase struct {
+		name    string
+		opts    []client.UpdateOptionFunc
+		options string
+	}
+
+	tests := []testCase{
+		{
+			name: "overwrite",
+		},
+		{
+			name:    "no overwrite",
+			opts:    []client.UpdateOptionFunc{client.UpdateSetNoOverwrite},
+			options: "noOverwrite",
+		},
+	}
+
+	for _, y := range tests {
+		t.Run(y.name, func(t *testing.T) {
+			ts := httptest.NewServer(
+				http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						assert.Equal(t, http.MethodPost, r.Method)
+						assert.Equal(t, "/ngsi-ld/v1/entityOperations/update", r.URL.Path)
+						assert.Equal(t, y.options, r.URL.Query().Get("options"))
+
+						w.WriteHeader(http.StatusNoContent)
+					}))
+			defer ts.Close()
+
+			cli, err := client.New(
+				client.SetURL(ts.URL),
+			)
+			assert.NoError(t, err)
+
+			err = cli.BatchUpdateEntities(context.Background(), testBatchPayload(), y.opts...)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func testLargeBatchPayload(size int) []*client.EntityWithContext {
+	payload := []*client.EntityWithContext{}
+	for i := 0; i < size; i++ {
+		payload = append(payload, &client.EntityWithContext{
+			Entity: &model.Entity{ID: fmt.Sprintf("urn:sensor:%d", i), Type: "Sensor"},
+		})
+	}
+	return payload
+}
+
+func TestBatchChunkedBySize(t *testing.T) {
+	var mu sync.Mutex
+	chunkSizes := []int{}
+	var inFlight, maxInFlight int32
+
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				current := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					seen := atomic.LoadInt32(&maxInFlight)
+					if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				body := []map[string]any{}
+				assert.NoError(t, json.Unmarshal(b, &body))
+
+				mu.Lock()
+				chunkSizes = append(chunkSizes, len(body))
+				mu.Unlock()
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+		client.SetBatchChunkSize(3),
+		client.SetBatchConcurrency(2),
+	)
+	assert.NoError(t, err)
+
+	err = cli.BatchUpsertEntities(context.Background(), testLargeBatchPayload(10))
+	assert.NoError(t, err)
+
+	sort.Ints(chunkSizes)
+	assert.Equal(t, []int{1, 3, 3, 3}, chunkSizes)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+}
+
+func TestBatchChunkedByBytes(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.LessOrEqual(t, len(b), 200)
+
+				mu.Lock()
+				requests++
+				mu.Unlock()
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+		client.SetBatchChunkBytes(200),
+	)
+	assert.NoError(t, err)
+
+	// Each entity takes about 60 bytes
+	err = cli.BatchCreateEntities(context.Background(), testLargeBatchPayload(10))
+	assert.NoError(t, err)
+	assert.Greater(t, requests, 3)
+}
+
+func TestBatchChunksMergedResult(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				body := []map[string]any{}
+				assert.NoError(t, json.Unmarshal(b, &body))
+
+				// The chunk holding urn:sensor:3 fails for it
+				for _, e := range body {
+					if e["id"] == "urn:sensor:3" {
+						w.Header().Set("Content-Type", "application/json")
+						w.WriteHeader(http.StatusMultiStatus)
+						_, err := w.Write([]byte(`{
+              "success": ["urn:sensor:2"],
+              "errors": [{"entityId": "urn:sensor:3", "error": {"type": "https://uri.etsi.org/ngsi-ld/errors/AlreadyExists"}}]
+            }`))
+						assert.NoError(t, err)
+						return
+					}
+				}
+				w.WriteHeader(http.StatusCreated)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+		client.SetBatchChunkSize(2),
+		client.SetBatchConcurrency(3),
+	)
+	assert.NoError(t, err)
+
+	err = cli.BatchCreateEntities(context.Background(), testLargeBatchPayload(6))
+
+	var batchErr *client.BatchError
+	assert.True(t, errors.As(err, &batchErr))
+	sort.Strings(batchErr.Result.Success)
+	assert.Equal(t, []string{"urn:sensor:0", "urn:sensor:1", "urn:sensor:2", "urn:sensor:4", "urn:sensor:5"}, batchErr.Result.Success)
+	assert.Equal(t, []string{"urn:sensor:3"}, batchErr.Result.FailedIDs())
+}
+
+func TestBatchChunkFailureStops(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				body := []map[string]any{}
+				assert.NoError(t, json.Unmarshal(b, &body))
+				atomic.AddInt32(&requests, 1)
+
+				// The second chunk fails as a whole
+				if body[0]["id"] == "urn:sensor:2" {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusBadRequest)
+					_, err := w.Write([]byte(`{"type": "https://uri.etsi.org/ngsi-ld/errors/InvalidRequest", "title": "Invalid request"}`))
+					assert.NoError(t, err)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+		client.SetBatchChunkSize(2),
+		client.SetBatchConcurrency(1),
+	)
+	assert.NoError(t, err)
+
+	err = cli.BatchUpsertEntities(context.Background(), testLargeBatchPayload(6))
+	assert.ErrorIs(t, err, client.ErrNgsiMixedResponse)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	var batchErr *client.BatchError
+	assert.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, []string{"urn:sensor:0", "urn:sensor:1"}, batchErr.Result.Success)
+	assert.Equal(t, []string{"urn:sensor:2", "urn:sensor:3", "urn:sensor:4", "urn:sensor:5"}, batchErr.Result.FailedIDs())
+
+	failed := batchErr.Result.Errors[0].Err()
+	assert.ErrorIs(t, failed, client.ErrNgsiLdInvalidRequest)
+	assert.ErrorContains(t, failed, "batch chunk 2 of 3")
+	assert.ErrorIs(t, batchErr.Result.Errors[3].Err(), client.ErrNgsiBatchNotSent)
+}
+
+func TestBatchCancelledContext(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+		client.SetBatchChunkSize(2),
+	)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = cli.BatchUpsertEntities(ctx, testLargeBatchPayload(4))
+
+	var batchErr *client.BatchError
+	assert.True(t, errors.As(err, &batchErr))
+	assert.Len(t, batchErr.Result.FailedIDs(), 4)
+	for _, item := range batchErr.Result.Errors {
+		assert.ErrorIs(t, item.Err(), context.Canceled)
+	}
+}
+
+func TestBatchInvalidChunkConfig(t *testing.T) {
+	_, err := client.New(
+		client.SetURL("unused"),
+		client.SetBatchChunkSize(0),
+	)
+	assert.ErrorIs(t, err, client.ErrInvalidBatchChunkSize)
+
+	_, err = client.New(
+		client.SetURL("unused"),
+		client.SetBatchConcurrency(-1),
+	)
+	assert.ErrorIs(t, err, client.ErrInvalidBatchConcurrency)
+}
+
+func TestBatchLinkHeaderContext(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Empty(t, r.Header.Get("Link"))
+
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.NotContains(t, string(b), `"@context":`)
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+		client.SetLinkHeaderContext,
+	)
+	assert.NoError(t, err)
+
+	// The core context is implied
+	err = cli.BatchCreateEntities(context.Background(), testBatchPayload())
+	assert.NoError(t, err)
+
+	// A single Link header can't describe entities with different contexts
+	payload := testBatchPayload()
+	payload[1].LdCtx = &ldcontext.LdContext{"https://example.org/context.jsonld"}
+	err = cli.BatchCreateEntities(context.Background(), payload)
+	assert.ErrorIs(t, err, client.ErrContextNotLinkable)
+}
+
+func TestBatchSystemAttributes(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/ngsi-ld/v1/entityOperations/update", r.URL.Path)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	// A retrieved entity can be sent back as an update, but not created
+	modifiedAt := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+	payload := []*client.EntityWithContext{{
+		Entity: &model.Entity{
+			ID:         "urn:sensor:1",
+			Type:       "Sensor",
+			ModifiedAt: &modifiedAt,
+			Properties: model.Properties{"temperature": {{Value: 21.5, ModifiedAt: &modifiedAt}}},
+		},
+	}}
+	err = cli.BatchUpdateEntities(context.Background(), payload)
+	assert.NoError(t, err)
+
+	err = cli.BatchCreateEntities(context.Background(), payload)
+	assert.ErrorIs(t, err, model.ErrSystemAttributeNotAllowed)
+}
+
+func TestBatchUpsertSystemAttributes(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("unused"),
+	)
+	assert.NoError(t, err)
+
+	// Upserted entities may be created
+	modifiedAt := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+	err = cli.BatchUpsertEntities(context.Background(), []*client.EntityWithContext{{
+		Entity: &model.Entity{
+			ID:         "urn:sensor:1",
+			Type:       "Sensor",
+			Properties: model.Properties{"temperature": {{Value: 21.5, ModifiedAt: &modifiedAt}}},
+		},
+	}})
+	assert.ErrorIs(t, err, model.ErrSystemAttributeNotAllowed)
+}
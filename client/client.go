@@ -3,7 +3,9 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"time"
 
@@ -14,13 +16,23 @@ import (
 var (
 	ngsiLdUserAgent = requestHeader{key: "User-Agent", value: "ngsild-client"}
 	jsonLdBody      = requestHeader{key: "Content-Type", value: "application/ld+json"}
+	jsonBody        = requestHeader{key: "Content-Type", value: "application/json"}
 	jsonResponse    = requestHeader{key: "Accept", value: "application/json"}
+	geoJSONResponse = requestHeader{key: "Accept", value: "application/geo+json"}
 )
 
 type NgsiLdClient struct {
 	c       *http.Client
 	url     string
 	headers map[string]string
+
+	// Batch operations are split in chunks sent concurrently
+	batchChunkSize  int
+	batchChunkBytes int
+	batchWorkers    int
+
+	// The @context of request bodies is sent in a Link header
+	linkContext bool
 }
 
 // OptionFunc is a function that configures a NgsiLdClient.
@@ -32,6 +44,7 @@ func New(options ...OptionFunc) (*NgsiLdClient, error) {
 
 	ngsiLdClient.c = &http.Client{}
 	ngsiLdClient.headers = map[string]string{}
+	ngsiLdClient.batchWorkers = 1
 
 	// apply the options
 	for _, option := range options {
@@ -55,7 +68,6 @@ func (c *NgsiLdClient) Validate() error {
 }
 
 type requestBody map[string]json.RawMessage
-type batchRequestBody []*requestBody
 
 type ProblemDetails struct {
 	ErrType string `json:"type"`
@@ -63,14 +75,108 @@ type ProblemDetails struct {
 	Detail  string `json:"detail"`
 }
 
+// problemDetailsErrors associates the NGSI-LD error types to the errors of this package
+var problemDetailsErrors = map[string]ErrNgsiLdOperation{
+	ngsiLdErrAlreadyExist:          ErrNgsiLdEntityExists,
+	ngsiLdErrBadData:               ErrNgsiBadData,
+	ngsiLdErrInvalidRequest:        ErrNgsiLdInvalidRequest,
+	ngsiLdErrResourceNotFound:      ErrNgsiLdResourceNotFound,
+	ngsiLdErrOperationNotSupported: ErrNgsiLdOperationNotSupported,
+	ngsiLdErrTooComplexQuery:       ErrNgsiLdTooComplexQuery,
+	ngsiLdErrTooManyResults:        ErrNgsiLdTooManyResults,
+	ngsiLdErrLdContextNotAvailable: ErrNgsiLdContextNotAvailable,
+	ngsiLdErrInternalError:         ErrNgsiLdInternalError,
+}
+
+// asError returns the error matching the problem type, nil when the type is unknown
+func (p *ProblemDetails) asError() error {
+	target, ok := problemDetailsErrors[p.ErrType]
+	if !ok {
+		return nil
+	}
+	if p.Detail == "" {
+		return target
+	}
+	return errors.Wrapf(target, "Detail: %s", p.Detail)
+}
+
+// responseError decodes the body of a failed response into a typed error,
+// falling back to a generic one when the body is not a known ProblemDetails
+func responseError(resp *http.Response) error {
+	bodyBytes, _ := ioutil.ReadAll(resp.Body)
+	return bodyError(resp.StatusCode, bodyBytes)
+}
+
+// batchResponseError decodes the body of a failed batch operation,
+// a partial failure becomes a *BatchError
+func batchResponseError(resp *http.Response) error {
+	bodyBytes, _ := ioutil.ReadAll(resp.Body)
+
+	// Some brokers report partial failures with error status codes too
+	multiError := BatchOperationResult{}
+	err := json.Unmarshal(bodyBytes, &multiError)
+	if err == nil && (resp.StatusCode == http.StatusMultiStatus || len(multiError.Errors) > 0) {
+		return &BatchError{Result: multiError}
+	}
+
+	return bodyError(resp.StatusCode, bodyBytes)
+}
+
+func bodyError(statusCode int, bodyBytes []byte) error {
+	problem := ProblemDetails{}
+	if err := json.Unmarshal(bodyBytes, &problem); err == nil {
+		if err := problem.asError(); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("Unexpected status code: '%d'\nResponse body: %s", statusCode, string(bodyBytes))
+}
+
 type BatchOperationResult struct {
 	Success []string                `json:"success"`
 	Errors  []BatchRequestErrorItem `json:"errors"`
 }
 
+// BatchError is returned when a batch operation fails for some of the entities,
+// Result holds the outcome for each of them
+type BatchError struct {
+	Result BatchOperationResult
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%s: %d succeeded, %d failed %v", ErrNgsiMixedResponse.Error(), len(e.Result.Success), len(e.Result.Errors), e.Result.FailedIDs())
+}
+
+// Unwrap allows matching a BatchError with errors.Is(err, ErrNgsiMixedResponse)
+func (e *BatchError) Unwrap() error {
+	return ErrNgsiMixedResponse
+}
+
+// FailedIDs returns the IDs of the entities the operation failed for
+func (r *BatchOperationResult) FailedIDs() []string {
+	ids := make([]string, 0, len(r.Errors))
+	for _, item := range r.Errors {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
 type BatchRequestErrorItem struct {
 	ID             string         `json:"@id"`
 	ProblemDetails ProblemDetails `json:"-"`
+	Cause          error          `json:"-"` // Failure of the whole request, when the entity was not reported on
+}
+
+// Err returns the error matching the problem reported for the entity
+func (item *BatchRequestErrorItem) Err() error {
+	if item.Cause != nil {
+		return errors.Wrapf(item.Cause, "ID: %s", item.ID)
+	}
+	if err := item.ProblemDetails.asError(); err != nil {
+		return errors.Wrapf(err, "ID: %s", item.ID)
+	}
+	return errors.Errorf("ID: %s, %s: %s", item.ID, item.ProblemDetails.Title, item.ProblemDetails.Detail)
 }
 
 func (item *BatchRequestErrorItem) UnmarshalJSON(b []byte) error {
@@ -80,13 +186,23 @@ func (item *BatchRequestErrorItem) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
+	// Keys differ among versions of the specification
 	id, ok := kv["@id"]
 	if !ok {
+		id, ok = kv["entityId"]
+	}
+	if !ok {
+		return errors.New("can't parse error item")
+	}
+	err = json.Unmarshal(id, &item.ID)
+	if err != nil {
 		return errors.New("can't parse error item")
 	}
-	item.ID = string(id)
 
 	problemString, ok := kv["ProblemDetails"]
+	if !ok {
+		problemString, ok = kv["error"]
+	}
 	if !ok {
 		return errors.New("can't parse error item")
 	}
@@ -101,27 +217,55 @@ func (item *BatchRequestErrorItem) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func addContext(payload json.Marshaler, ldCtx *ldcontext.LdContext) (requestBody, error) {
+// addContext builds the body of a request carrying the payload, along with the headers
+// describing it. The context is embedded in the body as @context, or referenced with a
+// Link header when the client is configured with SetLinkHeaderContext.
+func (client *NgsiLdClient) addContext(payload json.Marshaler, ldCtx *ldcontext.LdContext) (requestBody, []requestHeader, error) {
 	requestBody := requestBody{}
 	// struct -> json
 	serializedPayload, err := payload.MarshalJSON()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	// json -> key-value
 	err = json.Unmarshal(serializedPayload, &requestBody)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	headers, err := client.bodyHeaders(ldCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if client.linkContext {
+		return requestBody, headers, nil
 	}
 
 	// Add Context
 	serializedContext, err := json.Marshal(ldCtx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	requestBody["@context"] = serializedContext
 
-	return requestBody, nil
+	return requestBody, headers, nil
+}
+
+// bodyHeaders returns the headers of a request whose body refers to the context
+func (client *NgsiLdClient) bodyHeaders(ldCtx *ldcontext.LdContext) ([]requestHeader, error) {
+	if !client.linkContext {
+		return []requestHeader{jsonLdBody}, nil
+	}
+
+	headers := []requestHeader{jsonBody}
+	link, err := contextLink(ldCtx)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		headers = append(headers, *link)
+	}
+	return headers, nil
 }
 
 type requestHeader struct {
@@ -129,6 +273,23 @@ type requestHeader struct {
 	value string
 }
 
+// contextLink builds the Link header referencing the @context of requests
+// without one in the body. Empty or core contexts need no header, the core one is implied.
+func contextLink(ldCtx *ldcontext.LdContext) (*requestHeader, error) {
+	if ldCtx == nil {
+		return nil, nil
+	}
+	link, err := ldCtx.LinkHeader()
+	if err != nil {
+		return nil, err
+	}
+	if link == "" {
+		return nil, nil
+	}
+
+	return &requestHeader{key: "Link", value: link}, nil
+}
+
 func (c *NgsiLdClient) newRequest(ctx context.Context, method, url string, body io.Reader, headers ...requestHeader) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
@@ -137,7 +298,9 @@ func (c *NgsiLdClient) newRequest(ctx context.Context, method, url string, body
 
 	// expectation of a ngsi-ld client
 	headers = append(headers, ngsiLdUserAgent)
-	headers = append(headers, jsonResponse)
+	if !hasHeader(headers, jsonResponse.key) {
+		headers = append(headers, jsonResponse)
+	}
 
 	// set the global headers
 	for header, value := range c.headers {
@@ -150,6 +313,15 @@ func (c *NgsiLdClient) newRequest(ctx context.Context, method, url string, body
 	return req, nil
 }
 
+func hasHeader(headers []requestHeader, key string) bool {
+	for _, h := range headers {
+		if h.key == key {
+			return true
+		}
+	}
+	return false
+}
+
 // SetURL makes the client connect to the specified Context Broker.
 func SetURL(url string) OptionFunc {
 	return func(c *NgsiLdClient) error {
@@ -178,3 +350,45 @@ func SetGlobalHeader(key string, value string) OptionFunc {
 		return nil
 	}
 }
+
+// SetLinkHeaderContext sends the @context of request bodies in a Link header,
+// with application/json content type, instead of embedding it in the body.
+// Such contexts must be made of a single URL besides the core context.
+var SetLinkHeaderContext OptionFunc = func(c *NgsiLdClient) error {
+	c.linkContext = true
+	return nil
+}
+
+// SetBatchChunkSize splits the payload of batch operations in chunks of at most size entities
+func SetBatchChunkSize(size int) OptionFunc {
+	return func(client *NgsiLdClient) error {
+		if size <= 0 {
+			return ErrInvalidBatchChunkSize
+		}
+		client.batchChunkSize = size
+		return nil
+	}
+}
+
+// SetBatchChunkBytes splits the payload of batch operations in chunks of at most size bytes.
+// An entity bigger than size is sent alone.
+func SetBatchChunkBytes(size int) OptionFunc {
+	return func(client *NgsiLdClient) error {
+		if size <= 0 {
+			return ErrInvalidBatchChunkSize
+		}
+		client.batchChunkBytes = size
+		return nil
+	}
+}
+
+// SetBatchConcurrency sets how many chunks of a batch operation are sent at the same time
+func SetBatchConcurrency(workers int) OptionFunc {
+	return func(client *NgsiLdClient) error {
+		if workers <= 0 {
+			return ErrInvalidBatchConcurrency
+		}
+		client.batchWorkers = workers
+		return nil
+	}
+}
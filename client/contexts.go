@@ -0,0 +1,206 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/pkg/errors"
+)
+
+const contextsEndpoint string = "ngsi-ld/v1/jsonldContexts"
+
+// Kinds of the contexts known by the Context Broker
+const (
+	ContextKindHosted            = "Hosted"            // Registered by the users
+	ContextKindCached            = "Cached"            // Downloaded while processing requests
+	ContextKindImplicitlyCreated = "ImplicitlyCreated" // Created from the inline contexts of requests
+)
+
+type listContextsOptions struct {
+	kind string
+}
+
+type ContextsOptionFunc func(*listContextsOptions) error
+
+// ContextsSetKind lists only the contexts of the kind, e.g. ContextKindHosted
+func ContextsSetKind(kind string) ContextsOptionFunc {
+	return func(o *listContextsOptions) error {
+		switch kind {
+		case ContextKindHosted, ContextKindCached, ContextKindImplicitlyCreated:
+			o.kind = kind
+			return nil
+		}
+		return errors.Errorf("unknown context kind %q", kind)
+	}
+}
+
+// RegisterContext stores the context in the Context Broker, which serves it from then on.
+// The returned context refers to the hosted copy by URL, so it can be sent in the Link header.
+func (client *NgsiLdClient) RegisterContext(ctx context.Context, ldCtx ldcontext.LdContext) (ldcontext.LdContext, error) {
+	if len(ldCtx) == 0 {
+		return nil, errors.Wrap(ErrInvalidContextsOptions, "empty context")
+	}
+	err := ldCtx.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	registerRequestBody, err := json.Marshal(map[string]ldcontext.LdContext{"@context": ldCtx})
+	if err != nil {
+		return nil, err
+	}
+
+	registerURL := strings.Join([]string{client.url, contextsEndpoint}, "/")
+	req, err := client.newRequest(
+		ctx,
+		http.MethodPost,
+		registerURL,
+		bytes.NewBuffer(registerRequestBody),
+		jsonLdBody,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't register context")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, responseError(resp)
+	}
+
+	// The Context Broker tells where the context is served, possibly relative to its URL
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, errors.New("missing Location of the registered context")
+	}
+	base, err := url.Parse(registerURL)
+	if err != nil {
+		return nil, err
+	}
+	hosted, err := base.Parse(location)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid Location of the registered context: %s", location)
+	}
+	return ldcontext.FromURL(hosted.String())
+}
+
+// ListContexts returns the URLs of the contexts known by the Context Broker
+func (client *NgsiLdClient) ListContexts(ctx context.Context, opts ...ContextsOptionFunc) ([]string, error) {
+	requestOptions := &listContextsOptions{}
+	for _, o := range opts {
+		err := o(requestOptions)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidContextsOptions, err.Error())
+		}
+	}
+
+	listURL := strings.Join([]string{client.url, contextsEndpoint}, "/")
+	if requestOptions.kind != "" {
+		listURL += "?" + url.Values{"kind": []string{requestOptions.kind}}.Encode()
+	}
+	bodyBytes, err := client.getContexts(ctx, listURL)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := []string{}
+	err = json.Unmarshal(bodyBytes, &urls)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't decode contexts")
+	}
+	return urls, nil
+}
+
+// RetrieveContext returns the context served by the Context Broker.
+// The ID is the local identifier of the context or its URL.
+func (client *NgsiLdClient) RetrieveContext(ctx context.Context, id string) (ldcontext.LdContext, error) {
+	if id == "" {
+		return nil, errors.Wrap(ErrInvalidContextsOptions, "empty context ID")
+	}
+
+	retrieveURL := strings.Join([]string{client.url, contextsEndpoint, url.PathEscape(id)}, "/")
+	bodyBytes, err := client.getContexts(ctx, retrieveURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "ID: %s", id)
+	}
+
+	document := struct {
+		Context ldcontext.LdContext `json:"@context"`
+	}{}
+	err = json.Unmarshal(bodyBytes, &document)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't decode context %s", id)
+	}
+	if document.Context == nil {
+		return nil, errors.Errorf("missing @context in context %s", id)
+	}
+	return document.Context, nil
+}
+
+func (client *NgsiLdClient) getContexts(ctx context.Context, contextsURL string) ([]byte, error) {
+	req, err := client.newRequest(
+		ctx,
+		http.MethodGet,
+		contextsURL,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't retrieve contexts")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read contexts")
+	}
+	return bodyBytes, nil
+}
+
+// DeleteContext removes the context from the Context Broker.
+// The ID is the local identifier of the context or its URL.
+func (client *NgsiLdClient) DeleteContext(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.Wrap(ErrInvalidContextsOptions, "empty context ID")
+	}
+
+	deleteURL := strings.Join([]string{client.url, contextsEndpoint, url.PathEscape(id)}, "/")
+	req, err := client.newRequest(
+		ctx,
+		http.MethodDelete,
+		deleteURL,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "can't delete context")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return errors.Wrapf(responseError(resp), "ID: %s", id)
+}
@@ -0,0 +1,127 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phoops/ngsi-gold/client"
+	"github.com/phoops/ngsi-gold/ldcontext"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterContext(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/jsonldContexts", r.URL.Path)
+				assert.Equal(t, "application/ld+json", r.Header.Get("Content-Type"))
+
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				body := map[string]any{}
+				assert.NoError(t, json.Unmarshal(b, &body))
+				assert.Equal(t, map[string]any{
+					"@context": []any{map[string]any{"temperature": "https://example.org/temperature"}},
+				}, body)
+
+				w.Header().Set("Location", "/ngsi-ld/v1/jsonldContexts/ctx-1")
+				w.WriteHeader(http.StatusCreated)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	domain, err := ldcontext.FromTerms(map[string]any{"temperature": "https://example.org/temperature"})
+	assert.NoError(t, err)
+	hosted, err := cli.RegisterContext(context.Background(), domain)
+	assert.NoError(t, err)
+	assert.Equal(t, ldcontext.LdContext{ts.URL + "/ngsi-ld/v1/jsonldContexts/ctx-1"}, hosted)
+
+	_, err = cli.RegisterContext(context.Background(), ldcontext.EmptyContext)
+	assert.ErrorIs(t, err, client.ErrInvalidContextsOptions)
+}
+
+func TestListContexts(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/jsonldContexts", r.URL.Path)
+				assert.Equal(t, "Hosted", r.URL.Query().Get("kind"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`["http://broker/ngsi-ld/v1/jsonldContexts/ctx-1"]`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	urls, err := cli.ListContexts(context.Background(), client.ContextsSetKind(client.ContextKindHosted))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://broker/ngsi-ld/v1/jsonldContexts/ctx-1"}, urls)
+
+	_, err = cli.ListContexts(context.Background(), client.ContextsSetKind("Remote"))
+	assert.ErrorIs(t, err, client.ErrInvalidContextsOptions)
+}
+
+func TestRetrieveContext(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/jsonldContexts/ctx-1", r.URL.Path)
+
+				w.Header().Set("Content-Type", "application/ld+json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`{"@context": {"temperature": "https://example.org/temperature"}}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	ldCtx, err := cli.RetrieveContext(context.Background(), "ctx-1")
+	assert.NoError(t, err)
+	assert.Equal(t, ldcontext.LdContext{map[string]any{"temperature": "https://example.org/temperature"}}, ldCtx)
+}
+
+func TestDeleteContext(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodDelete, r.Method)
+				// Contexts can be identified by URL too
+				assert.Equal(t, "/ngsi-ld/v1/jsonldContexts/https:%2F%2Fexample.org%2Fcontext.jsonld", r.URL.EscapedPath())
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, err := w.Write([]byte(`{"type": "https://uri.etsi.org/ngsi-ld/errors/ResourceNotFound", "title": "Context not found"}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.DeleteContext(context.Background(), "https://example.org/context.jsonld")
+	assert.ErrorIs(t, err, client.ErrNgsiLdResourceNotFound)
+}
@@ -14,7 +14,7 @@ import (
 	"github.com/pkg/errors"
 )
 
-const createEntityEndpoint string = "ngsi-ld/v1/entities"
+const entitiesEndpoint string = "ngsi-ld/v1/entities"
 
 func (client *NgsiLdClient) CreateEntity(ctx context.Context, ldCtx *ldcontext.LdContext, entity *model.Entity) error {
 	// Set default context whenever missing
@@ -23,13 +23,13 @@ func (client *NgsiLdClient) CreateEntity(ctx context.Context, ldCtx *ldcontext.L
 	}
 
 	// Validate entity to be created before contacting the server
-	err := entity.Validate(true)
+	err := entity.ValidateCreation()
 	if err != nil {
 		return errors.Wrap(err, "invalid Entity")
 	}
 
-	createURL := strings.Join([]string{client.url, createEntityEndpoint}, "/")
-	createRequest, err := addContext(entity, ldCtx)
+	createURL := strings.Join([]string{client.url, entitiesEndpoint}, "/")
+	createRequest, bodyHeaders, err := client.addContext(entity, ldCtx)
 	if err != nil {
 		return err
 	}
@@ -43,7 +43,7 @@ func (client *NgsiLdClient) CreateEntity(ctx context.Context, ldCtx *ldcontext.L
 		http.MethodPost,
 		createURL,
 		bytes.NewBuffer(createRequestBody),
-		jsonLdBody,
+		bodyHeaders...,
 	)
 	if err != nil {
 		return err
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/phoops/ngsi-gold/client"
 	"github.com/phoops/ngsi-gold/ldcontext"
@@ -55,6 +56,44 @@ func TestCreateSuccess(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCreateLinkHeaderContext(t *testing.T) {
+	testEntity := model.Entity{
+		ID:   "entity:1",
+		Type: "thing",
+	}
+
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Context travels in the Link header, not in the body
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, `<https://example.org/context.jsonld>; rel="http://www.w3.org/ns/json-ld#context"; type="application/ld+json"`, r.Header.Get("Link"))
+
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.NotContains(t, string(b), `"@context":`)
+
+				w.WriteHeader(http.StatusCreated)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+		client.SetLinkHeaderContext,
+	)
+	assert.NoError(t, err)
+
+	ldCtx := ldcontext.LdContext{"https://example.org/context.jsonld"}.WithCore()
+	err = cli.CreateEntity(context.Background(), &ldCtx, &testEntity)
+	assert.NoError(t, err)
+
+	// Inline contexts can't travel in a Link header
+	inline, err := ldcontext.FromTerms(map[string]any{"thing": "https://example.org/thing"})
+	assert.NoError(t, err)
+	err = cli.CreateEntity(context.Background(), &inline, &testEntity)
+	assert.ErrorIs(t, err, client.ErrContextNotLinkable)
+}
+
 func TestCreateGenericFailure(t *testing.T) {
 	testEntity := model.Entity{
 		ID:   "entity:1",
@@ -155,3 +194,27 @@ func TestCreateEntityValidation(t *testing.T) {
 	assert.Error(t, err)
 	assert.ErrorContains(t, err, "invalid Entity")
 }
+
+func TestCreateEntitySystemAttributes(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	testEntity := model.Entity{
+		ID:   "entity:1",
+		Type: "Room",
+		Properties: model.Properties{
+			"temperature": {{Value: 21.5, CreatedAt: &createdAt}},
+		},
+	}
+
+	cli, err := client.New(
+		client.SetURL("unused"),
+	)
+
+	assert.NoError(t, err)
+
+	err = cli.CreateEntity(
+		context.Background(),
+		&ldcontext.DefaultContext,
+		&testEntity,
+	)
+	assert.ErrorIs(t, err, model.ErrSystemAttributeNotAllowed)
+}
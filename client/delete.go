@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/pkg/errors"
+)
+
+const batchDeleteEndpoint string = "ngsi-ld/v1/entityOperations/delete"
+
+// DeleteEntity removes the Entity and all its Attributes
+func (client *NgsiLdClient) DeleteEntity(ctx context.Context, id string) error {
+	if id == "" {
+		return model.ErrEntityMissingID
+	}
+
+	deleteURL := strings.Join([]string{client.url, entitiesEndpoint, url.PathEscape(id)}, "/")
+	req, err := client.newRequest(
+		ctx,
+		http.MethodDelete,
+		deleteURL,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "can't delete Entity")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return errors.Wrapf(responseError(resp), "ID: %s", id)
+}
+
+// BatchDeleteEntities deletes the entities having the listed IDs.
+// When only some of them can be deleted a *BatchError is returned.
+func (client *NgsiLdClient) BatchDeleteEntities(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	batchRequest := make([]batchItem, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			return errors.Wrap(model.ErrEntityMissingID, "invalid Entity")
+		}
+		body, err := json.Marshal(id)
+		if err != nil {
+			return err
+		}
+		batchRequest = append(batchRequest, batchItem{id: id, body: body})
+	}
+
+	return client.runBatch(ctx, batchDeleteEndpoint, batchRequest, []requestHeader{jsonBody}, nil)
+}
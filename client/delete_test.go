@@ -0,0 +1,138 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phoops/ngsi-gold/client"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteSuccess(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Assertion on the received request
+				assert.Equal(t, http.MethodDelete, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/entities/urn:sensor:1", r.URL.Path)
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.DeleteEntity(context.Background(), "urn:sensor:1")
+	assert.NoError(t, err)
+}
+
+func TestDeleteNotFound(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, err := w.Write([]byte(`{"type": "https://uri.etsi.org/ngsi-ld/errors/ResourceNotFound", "title": "Entity not found"}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.DeleteEntity(context.Background(), "urn:sensor:1")
+	assert.ErrorIs(t, err, client.ErrNgsiLdResourceNotFound)
+}
+
+func TestBatchDeleteSuccess(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Assertion on the received request
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/entityOperations/delete", r.URL.Path)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, `["urn:sensor:1","urn:sensor:2"]`, string(b))
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.BatchDeleteEntities(context.Background(), []string{"urn:sensor:1", "urn:sensor:2"})
+	assert.NoError(t, err)
+}
+
+func TestBatchDeletePartialFailure(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusMultiStatus)
+				_, err := w.Write([]byte(`{
+          "success": ["urn:sensor:1"],
+          "errors": [
+            {
+              "@id": "urn:sensor:2",
+              "ProblemDetails": {
+                "type": "https://uri.etsi.org/ngsi-ld/errors/ResourceNotFound",
+                "title": "Entity not found"
+              }
+            }
+          ]
+        }`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.BatchDeleteEntities(context.Background(), []string{"urn:sensor:1", "urn:sensor:2"})
+	assert.ErrorIs(t, err, client.ErrNgsiMixedResponse)
+
+	var batchErr *client.BatchError
+	assert.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, []string{"urn:sensor:1"}, batchErr.Result.Success)
+	assert.Equal(t, []string{"urn:sensor:2"}, batchErr.Result.FailedIDs())
+	assert.ErrorIs(t, batchErr.Result.Errors[0].Err(), client.ErrNgsiLdResourceNotFound)
+}
+
+func TestBatchDeleteBadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, err := w.Write([]byte(`{"type": "https://uri.etsi.org/ngsi-ld/errors/BadRequestData", "title": "Bad request", "detail": "invalid id"}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.BatchDeleteEntities(context.Background(), []string{"sensor"})
+	assert.ErrorIs(t, err, client.ErrNgsiBadData)
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"github.com/phoops/ngsi-gold/ldcontext"
 	"github.com/pkg/errors"
 )
 
@@ -10,11 +11,19 @@ type ErrInvalidClientConfig error
 var ErrMissingURL ErrInvalidClientConfig = errors.New("invalid client configuration: missing URL")
 var ErrNegativeTimeout ErrInvalidClientConfig = errors.New("invalid client configuration: negative HTTP timeout")
 var ErrWrongCustomHeaderFormat ErrInvalidClientConfig = errors.New("invalid client configuration: key or value of a custom header is empty")
+var ErrInvalidBatchChunkSize ErrInvalidClientConfig = errors.New("invalid client configuration: batch chunk size must be positive")
+var ErrInvalidBatchConcurrency ErrInvalidClientConfig = errors.New("invalid client configuration: batch concurrency must be positive")
 
 // NGSI-LD errors URIs
 var ngsiLdErrAlreadyExist = "https://uri.etsi.org/ngsi-ld/errors/AlreadyExists"
 var ngsiLdErrBadData = "https://uri.etsi.org/ngsi-ld/errors/BadRequestData"
 var ngsiLdErrInvalidRequest = "https://uri.etsi.org/ngsi-ld/errors/InvalidRequest"
+var ngsiLdErrResourceNotFound = "https://uri.etsi.org/ngsi-ld/errors/ResourceNotFound"
+var ngsiLdErrOperationNotSupported = "https://uri.etsi.org/ngsi-ld/errors/OperationNotSupported"
+var ngsiLdErrTooComplexQuery = "https://uri.etsi.org/ngsi-ld/errors/TooComplexQuery"
+var ngsiLdErrTooManyResults = "https://uri.etsi.org/ngsi-ld/errors/TooManyResults"
+var ngsiLdErrLdContextNotAvailable = "https://uri.etsi.org/ngsi-ld/errors/LdContextNotAvailable"
+var ngsiLdErrInternalError = "https://uri.etsi.org/ngsi-ld/errors/InternalError"
 
 // Operations
 type ErrNgsiLdOperation error
@@ -23,8 +32,29 @@ var ErrNgsiLdEntityExists ErrNgsiLdOperation = errors.New("Entity already exists
 var ErrNgsiBadData ErrNgsiLdOperation = errors.New("Bad request")
 var ErrNgsiLdInvalidRequest ErrNgsiLdOperation = errors.New("Invalid JSON of the request")
 var ErrNgsiMixedResponse ErrNgsiLdOperation = errors.New("Mixed errors")
+var ErrNgsiBatchNotSent ErrNgsiLdOperation = errors.New("Batch chunk not sent")
+var ErrNgsiLdResourceNotFound ErrNgsiLdOperation = errors.New("Resource not found")
+var ErrNgsiLdOperationNotSupported ErrNgsiLdOperation = errors.New("Operation not supported")
+var ErrNgsiLdTooComplexQuery ErrNgsiLdOperation = errors.New("Query too complex")
+var ErrNgsiLdTooManyResults ErrNgsiLdOperation = errors.New("Too many results")
+var ErrNgsiLdContextNotAvailable ErrNgsiLdOperation = errors.New("JSON-LD context not available")
+var ErrNgsiLdInternalError ErrNgsiLdOperation = errors.New("Internal error of the Context Broker")
 
 // Options
 type ErrInvalidOptions error
 
 var ErrInvalidUpsertOptions ErrInvalidOptions = errors.New("Invalid options provided for Upsert operation")
+var ErrInvalidUpdateOptions ErrInvalidOptions = errors.New("Invalid options provided for Update operation")
+var ErrInvalidRetrieveOptions ErrInvalidOptions = errors.New("Invalid options provided for Retrieve operation")
+var ErrInvalidQueryOptions ErrInvalidOptions = errors.New("Invalid options provided for Query operation")
+var ErrInvalidAppendOptions ErrInvalidOptions = errors.New("Invalid options provided for Append operation")
+var ErrInvalidAttributeOptions ErrInvalidOptions = errors.New("Invalid options provided for Attribute operation")
+var ErrInvalidSubscriptionOptions ErrInvalidOptions = errors.New("Invalid options provided for Subscription operation")
+var ErrInvalidTemporalOptions ErrInvalidOptions = errors.New("Invalid options provided for temporal operation")
+var ErrInvalidContextsOptions ErrInvalidOptions = errors.New("Invalid options provided for context operation")
+var ErrInvalidNotificationOptions ErrInvalidOptions = errors.New("Invalid options provided for notification handler")
+
+// JSON-LD context
+type ErrInvalidContext error
+
+var ErrContextNotLinkable ErrInvalidContext = ldcontext.ErrContextNotLinkable
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/phoops/ngsi-gold/model"
+)
+
+// defaultPageSize is the number of entities fetched at once when the query sets no limit
+const defaultPageSize = 100
+
+// EntityIterator walks every page of the results of an entity query.
+//
+//	it, err := cli.IterateEntities(ldCtx, client.QuerySetTypes("Room"))
+//	for it.Next(ctx) {
+//		entity := it.Entity()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type EntityIterator struct {
+	client  *NgsiLdClient
+	ldCtx   *ldcontext.LdContext
+	options *queryEntitiesOptions
+
+	page  []model.Entity
+	index int
+	total int
+	done  bool
+	err   error
+}
+
+// IterateEntities prepares an iterator over the entities matching the options.
+// The page size is given by QuerySetLimit (defaultPageSize when missing), while
+// QuerySetOffset sets the first entity to return.
+func (client *NgsiLdClient) IterateEntities(ldCtx *ldcontext.LdContext, opts ...QueryOptionFunc) (*EntityIterator, error) {
+	requestOptions, err := newQueryEntitiesOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if requestOptions.limit == 0 {
+		requestOptions.limit = defaultPageSize
+	}
+	requestOptions.count = true
+
+	return &EntityIterator{
+		client:  client,
+		ldCtx:   ldCtx,
+		options: requestOptions,
+		index:   -1,
+		total:   -1,
+	}, nil
+}
+
+// Next advances to the following entity, fetching a new page when needed.
+// It returns false when the results are over, the context is cancelled or an
+// error occurs: check Err to tell them apart.
+func (it *EntityIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	it.index++
+	if it.index < len(it.page) {
+		return true
+	}
+	if it.done {
+		return false
+	}
+
+	page, total, err := it.client.queryEntities(ctx, it.ldCtx, it.options)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.page = page
+	it.index = 0
+	it.total = total
+	it.options.offset += len(page)
+
+	// Brokers may return pages shorter than the limit, e.g. when they cap it:
+	// only the count tells when the results are over. Without count a short
+	// page is the last one.
+	switch {
+	case len(page) == 0:
+		it.done = true
+	case total >= 0:
+		it.done = it.options.offset >= total
+	default:
+		it.done = len(page) < it.options.limit
+	}
+	return len(page) > 0
+}
+
+// Entity returns the current entity, nil before the first call to Next
+// or after the iteration is over
+func (it *EntityIterator) Entity() *model.Entity {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
+	}
+	return &it.page[it.index]
+}
+
+// Total returns the number of entities matching the query, as counted by the
+// Context Broker. It is known only after the first call to Next.
+func (it *EntityIterator) Total() (int, bool) {
+	return it.total, it.total >= 0
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *EntityIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,190 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/phoops/ngsi-gold/client"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pagedEntities serves total entities, honouring limit, offset and count
+func pagedEntities(t *testing.T, total int, requests *int) *httptest.Server {
+	return cappedEntities(t, total, 0, true, requests)
+}
+
+// cappedEntities serves total entities as pagedEntities, returning at most maxLimit
+// entities per page when positive, and the count only when asked to
+func cappedEntities(t *testing.T, total, maxLimit int, count bool, requests *int) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				*requests++
+				assert.Equal(t, "true", r.URL.Query().Get("count"))
+				limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+				assert.NoError(t, err)
+				offset := 0
+				if o := r.URL.Query().Get("offset"); o != "" {
+					offset, err = strconv.Atoi(o)
+					assert.NoError(t, err)
+				}
+				if maxLimit > 0 && limit > maxLimit {
+					limit = maxLimit
+				}
+
+				page := []map[string]any{}
+				for i := offset; i < total && i < offset+limit; i++ {
+					page = append(page, map[string]any{"id": fmt.Sprintf("urn:room:%d", i), "type": "Room"})
+				}
+				b, err := json.Marshal(page)
+				assert.NoError(t, err)
+
+				w.Header().Set("Content-Type", "application/json")
+				if count {
+					w.Header().Set("NGSILD-Results-Count", strconv.Itoa(total))
+				}
+				w.WriteHeader(http.StatusOK)
+				_, err = w.Write(b)
+				assert.NoError(t, err)
+			}))
+}
+
+func TestIterateAllPages(t *testing.T) {
+	requests := 0
+	ts := pagedEntities(t, 250, &requests)
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	it, err := cli.IterateEntities(nil, client.QuerySetTypes("Room"))
+	assert.NoError(t, err)
+
+	_, known := it.Total()
+	assert.False(t, known)
+
+	seen := 0
+	for it.Next(context.Background()) {
+		assert.Equal(t, fmt.Sprintf("urn:room:%d", seen), it.Entity().ID)
+		seen++
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 250, seen)
+	assert.Equal(t, 3, requests)
+	assert.Nil(t, it.Entity())
+
+	total, known := it.Total()
+	assert.True(t, known)
+	assert.Equal(t, 250, total)
+}
+
+func TestIterateCappedLimit(t *testing.T) {
+	requests := 0
+	ts := cappedEntities(t, 250, 30, true, &requests)
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	it, err := cli.IterateEntities(nil, client.QuerySetTypes("Room"), client.QuerySetLimit(100))
+	assert.NoError(t, err)
+
+	seen := 0
+	for it.Next(context.Background()) {
+		assert.Equal(t, fmt.Sprintf("urn:room:%d", seen), it.Entity().ID)
+		seen++
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 250, seen)
+	assert.Equal(t, 9, requests)
+}
+
+func TestIterateWithoutCount(t *testing.T) {
+	requests := 0
+	ts := cappedEntities(t, 250, 0, false, &requests)
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	it, err := cli.IterateEntities(nil, client.QuerySetTypes("Room"))
+	assert.NoError(t, err)
+
+	seen := 0
+	for it.Next(context.Background()) {
+		seen++
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 250, seen)
+	assert.Equal(t, 3, requests)
+
+	_, known := it.Total()
+	assert.False(t, known)
+}
+
+func TestIteratePageSize(t *testing.T) {
+	requests := 0
+	ts := pagedEntities(t, 40, &requests)
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	it, err := cli.IterateEntities(nil, client.QuerySetTypes("Room"), client.QuerySetLimit(10), client.QuerySetOffset(5))
+	assert.NoError(t, err)
+
+	seen := 0
+	for it.Next(context.Background()) {
+		seen++
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 35, seen)
+	// Last page is full, the count tells the iteration is over
+	assert.Equal(t, 4, requests)
+}
+
+func TestIterateCancelled(t *testing.T) {
+	requests := 0
+	ts := pagedEntities(t, 40, &requests)
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	it, err := cli.IterateEntities(nil, client.QuerySetTypes("Room"), client.QuerySetLimit(10))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.True(t, it.Next(ctx))
+	cancel()
+	assert.False(t, it.Next(ctx))
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+	assert.Equal(t, 1, requests)
+}
+
+func TestIterateInvalidOptions(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("unused"),
+	)
+	assert.NoError(t, err)
+
+	it, err := cli.IterateEntities(nil)
+	assert.Nil(t, it)
+	assert.ErrorIs(t, err, client.ErrInvalidQueryOptions)
+}
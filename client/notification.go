@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/pkg/errors"
+)
+
+// NotificationCallback receives the notifications accepted by a NotificationHandler.
+// Returning an error makes the handler answer with an internal error.
+type NotificationCallback func(ctx context.Context, notification *model.Notification) error
+
+// NotificationHandler is an http.Handler receiving the notifications of the
+// Context Broker, in the normalized or keyValues format.
+//
+//	handler, err := client.NewNotificationHandler(callback)
+//	...
+//	http.Handle("/notify", handler)
+type NotificationHandler struct {
+	callback NotificationCallback
+}
+
+// NewNotificationHandler returns a handler passing the received notifications to the callback
+func NewNotificationHandler(callback NotificationCallback) (*NotificationHandler, error) {
+	if callback == nil {
+		return nil, errors.Wrap(ErrInvalidNotificationOptions, "missing callback")
+	}
+	return &NotificationHandler{callback: callback}, nil
+}
+
+func (h *NotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeProblem(w, http.StatusMethodNotAllowed, ngsiLdErrOperationNotSupported, "Notifications must be POSTed", r.Method)
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "application/json" && mediaType != "application/ld+json") {
+		writeProblem(w, http.StatusUnsupportedMediaType, ngsiLdErrInvalidRequest, "Unsupported content type", r.Header.Get("Content-Type"))
+		return
+	}
+
+	bodyBytes, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, ngsiLdErrInvalidRequest, "Can't read notification", err.Error())
+		return
+	}
+
+	notification, err := decodeNotification(bodyBytes)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, ngsiLdErrBadData, "Invalid notification", err.Error())
+		return
+	}
+
+	err = h.callback(r.Context(), notification)
+	if err != nil {
+		writeProblem(w, http.StatusInternalServerError, ngsiLdErrInternalError, "Notification not processed", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeNotification(b []byte) (*model.Notification, error) {
+	envelope := struct {
+		ID             string            `json:"id"`
+		Type           string            `json:"type"`
+		SubscriptionID string            `json:"subscriptionId"`
+		NotifiedAt     time.Time         `json:"notifiedAt"`
+		Data           []json.RawMessage `json:"data"`
+	}{}
+	err := json.Unmarshal(b, &envelope)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.Type != "Notification" {
+		return nil, errors.Wrapf(ErrNgsiBadData, "unexpected type %q", envelope.Type)
+	}
+
+	notification := &model.Notification{
+		ID:             envelope.ID,
+		SubscriptionID: envelope.SubscriptionID,
+		NotifiedAt:     envelope.NotifiedAt,
+		Data:           make([]model.Entity, 0, len(envelope.Data)),
+	}
+	for _, item := range envelope.Data {
+		representation := entityRepresentation{keyValues: isKeyValues(item)}
+		entity, err := representation.decode(item)
+		if err != nil {
+			return nil, err
+		}
+		notification.Data = append(notification.Data, *entity)
+	}
+	return notification, nil
+}
+
+// writeProblem answers with a ProblemDetails body
+func writeProblem(w http.ResponseWriter, statusCode int, errType, title, detail string) {
+	body, _ := json.Marshal(ProblemDetails{ErrType: errType, Title: title, Detail: detail})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,128 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/phoops/ngsi-gold/client"
+	"github.com/phoops/ngsi-gold/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func postNotification(handler http.Handler, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNotificationNormalized(t *testing.T) {
+	var received *model.Notification
+	handler, err := client.NewNotificationHandler(func(ctx context.Context, n *model.Notification) error {
+		received = n
+		return nil
+	})
+	assert.NoError(t, err)
+
+	rec := postNotification(handler, "application/ld+json", `{
+    "id": "urn:ngsi-ld:Notification:1",
+    "type": "Notification",
+    "subscriptionId": "urn:ngsi-ld:Subscription:1",
+    "notifiedAt": "2023-02-13T11:30:40.123Z",
+    "data": [
+      {
+        "@context": "https://uri.etsi.org/ngsi-ld/v1/ngsi-ld-core-context.jsonld",
+        "id": "urn:room:1",
+        "type": "Room",
+        "temperature": {"type": "Property", "value": 21.5},
+        "wall": {"type": "Relationship", "object": "urn:wall:1"}
+      }
+    ]
+  }`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	assert.NotNil(t, received)
+	assert.Equal(t, "urn:ngsi-ld:Notification:1", received.ID)
+	assert.Equal(t, "urn:ngsi-ld:Subscription:1", received.SubscriptionID)
+	assert.Equal(t, 2023, received.NotifiedAt.Year())
+	assert.Len(t, received.Data, 1)
+	assert.Equal(t, 21.5, received.Data[0].Properties["temperature"][0].Value)
+	assert.Equal(t, "urn:wall:1", received.Data[0].Relationships["wall"][0].Object)
+}
+
+func TestNotificationKeyValues(t *testing.T) {
+	var received *model.Notification
+	handler, err := client.NewNotificationHandler(func(ctx context.Context, n *model.Notification) error {
+		received = n
+		return nil
+	})
+	assert.NoError(t, err)
+
+	rec := postNotification(handler, "application/json; charset=utf-8", `{
+    "id": "urn:ngsi-ld:Notification:1",
+    "type": "Notification",
+    "subscriptionId": "urn:ngsi-ld:Subscription:1",
+    "notifiedAt": "2023-02-13T11:30:40.123Z",
+    "data": [
+      {
+        "id": "urn:room:1",
+        "type": "Room",
+        "temperature": 21.5,
+        "name": null,
+        "location": {"type": "Point", "coordinates": [11.25, 43.77]}
+      }
+    ]
+  }`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	assert.Len(t, received.Data, 1)
+	assert.Equal(t, 21.5, received.Data[0].Properties["temperature"][0].Value)
+	assert.NotContains(t, received.Data[0].Properties, "name")
+	assert.NotNil(t, received.Data[0].Location)
+}
+
+func TestNotificationErrors(t *testing.T) {
+	handler, err := client.NewNotificationHandler(func(ctx context.Context, n *model.Notification) error {
+		if n.SubscriptionID == "urn:failing" {
+			return errors.New("storage unavailable")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// Wrong method
+	req := httptest.NewRequest(http.MethodGet, "/notify", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+
+	// Wrong content type
+	rec = postNotification(handler, "text/plain", `{}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	// Malformed body
+	rec = postNotification(handler, "application/json", `{"type": "Notification", "data": [{"id": 1}]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "https://uri.etsi.org/ngsi-ld/errors/BadRequestData")
+
+	// Not a notification
+	rec = postNotification(handler, "application/json", `{"type": "Subscription"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Callback failure
+	rec = postNotification(handler, "application/json", `{"type": "Notification", "subscriptionId": "urn:failing", "data": []}`)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "storage unavailable")
+}
+
+func TestNotificationHandlerNilCallback(t *testing.T) {
+	_, err := client.NewNotificationHandler(nil)
+	assert.ErrorIs(t, err, client.ErrInvalidNotificationOptions)
+}
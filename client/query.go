@@ -0,0 +1,328 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/phoops/ngsi-gold/query"
+	"github.com/pkg/errors"
+)
+
+const resultsCountHeader = "NGSILD-Results-Count"
+
+type queryEntitiesOptions struct {
+	// Representation of the matching entities
+	retrieveEntityOptions
+
+	ids       []string
+	types     []string
+	idPattern string
+	q         query.Term
+	geoQ      *model.GeoQuery
+	scopeQ    query.Term
+	csf       query.Term
+	limit     int
+	offset    int
+	count     bool
+}
+
+type QueryOptionFunc func(*queryEntitiesOptions) error
+
+// QuerySetIDs matches the entities having one of the listed IDs
+func QuerySetIDs(ids ...string) QueryOptionFunc {
+	return func(o *queryEntitiesOptions) error {
+		for _, id := range ids {
+			if id == "" {
+				return errors.New("empty entity ID")
+			}
+		}
+		o.ids = append(o.ids, ids...)
+		return nil
+	}
+}
+
+// QuerySetTypes matches the entities having one of the listed types
+func QuerySetTypes(types ...string) QueryOptionFunc {
+	return func(o *queryEntitiesOptions) error {
+		for _, t := range types {
+			if t == "" {
+				return errors.New("empty entity type")
+			}
+		}
+		o.types = append(o.types, types...)
+		return nil
+	}
+}
+
+// QuerySetIDPattern matches the entities whose ID matches the regular expression
+func QuerySetIDPattern(pattern string) QueryOptionFunc {
+	return func(o *queryEntitiesOptions) error {
+		if pattern == "" {
+			return errors.New("empty ID pattern")
+		}
+		o.idPattern = pattern
+		return nil
+	}
+}
+
+// QuerySetAttrs matches the entities having any of the listed Attributes,
+// and restricts the returned Attributes to them
+func QuerySetAttrs(attrs ...string) QueryOptionFunc {
+	return func(o *queryEntitiesOptions) error {
+		return RetrieveSetAttrs(attrs...)(&o.retrieveEntityOptions)
+	}
+}
+
+// QuerySetQ matches the entities satisfying the query
+func QuerySetQ(q query.Term) QueryOptionFunc {
+	return func(o *queryEntitiesOptions) error {
+		if q == nil || q.String() == "" {
+			return errors.New("empty query")
+		}
+		if err := query.Validate(q); err != nil {
+			return err
+		}
+		o.q = q
+		return nil
+	}
+}
+
+// QuerySetGeoQuery matches the entities satisfying the geospatial query
+func QuerySetGeoQuery(geoQ model.GeoQuery) QueryOptionFunc {
+	return func(o *queryEntitiesOptions) error {
+		if err := geoQ.Validate(true); err != nil {
+			return err
+		}
+		o.geoQ = &geoQ
+		return nil
+	}
+}
+
+// QuerySetScopeQ matches the entities whose scope satisfies the query
+func QuerySetScopeQ(scopeQ query.Term) QueryOptionFunc {
+	return func(o *queryEntitiesOptions) error {
+		if scopeQ == nil || scopeQ.String() == "" {
+			return errors.New("empty scope query")
+		}
+		if err := query.Validate(scopeQ); err != nil {
+			return err
+		}
+		o.scopeQ = scopeQ
+		return nil
+	}
+}
+
+// QuerySetCSF restricts the Context Sources involved to the ones satisfying the filter
+func QuerySetCSF(csf query.Term) QueryOptionFunc {
+	return func(o *queryEntitiesOptions) error {
+		if csf == nil || csf.String() == "" {
+			return errors.New("empty context source filter")
+		}
+		if err := query.Validate(csf); err != nil {
+			return err
+		}
+		o.csf = csf
+		return nil
+	}
+}
+
+// QuerySetLimit caps the number of returned entities
+func QuerySetLimit(limit int) QueryOptionFunc {
+	return func(o *queryEntitiesOptions) error {
+		if limit <= 0 {
+			return errors.New("limit must be positive")
+		}
+		o.limit = limit
+		return nil
+	}
+}
+
+// QuerySetOffset skips the first entities of the results
+func QuerySetOffset(offset int) QueryOptionFunc {
+	return func(o *queryEntitiesOptions) error {
+		if offset < 0 {
+			return errors.New("offset can't be negative")
+		}
+		o.offset = offset
+		return nil
+	}
+}
+
+// QuerySetSysAttrs asks for the system generated attributes (createdAt, modifiedAt)
+var QuerySetSysAttrs QueryOptionFunc = func(o *queryEntitiesOptions) error {
+	return RetrieveSetSysAttrs(&o.retrieveEntityOptions)
+}
+
+// QuerySetKeyValues asks for the simplified representation of the entities,
+// see RetrieveSetKeyValues
+var QuerySetKeyValues QueryOptionFunc = func(o *queryEntitiesOptions) error {
+	return RetrieveSetKeyValues(&o.retrieveEntityOptions)
+}
+
+// QuerySetGeometryProperty asks for the GeoJSON representation of the entities,
+// using the named GeoProperty as geometry of the features
+func QuerySetGeometryProperty(name string) QueryOptionFunc {
+	return func(o *queryEntitiesOptions) error {
+		return RetrieveSetGeometryProperty(name)(&o.retrieveEntityOptions)
+	}
+}
+
+func newQueryEntitiesOptions(opts []QueryOptionFunc) (*queryEntitiesOptions, error) {
+	requestOptions := &queryEntitiesOptions{}
+	for _, o := range opts {
+		err := o(requestOptions)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidQueryOptions, err.Error())
+		}
+	}
+
+	if !requestOptions.hasSelector() {
+		return nil, errors.Wrap(ErrInvalidQueryOptions, errMissingQuerySelector.Error())
+	}
+	return requestOptions, nil
+}
+
+var errMissingQuerySelector = errors.New("at least one of IDs, types, attrs, q, scopeQ, geoQ or csf is required")
+
+// hasSelector tells if the entities to return are restricted,
+// Context Brokers refuse to return every entity they know
+func (o *queryEntitiesOptions) hasSelector() bool {
+	return len(o.ids) > 0 ||
+		len(o.types) > 0 ||
+		len(o.attrs) > 0 ||
+		o.q != nil ||
+		o.scopeQ != nil ||
+		o.geoQ != nil ||
+		o.csf != nil
+}
+
+func (o *queryEntitiesOptions) query() (url.Values, error) {
+	q := o.retrieveEntityOptions.query()
+
+	if len(o.ids) > 0 {
+		q.Set("id", strings.Join(o.ids, ","))
+	}
+	if len(o.types) > 0 {
+		q.Set("type", strings.Join(o.types, ","))
+	}
+	if o.idPattern != "" {
+		q.Set("idPattern", o.idPattern)
+	}
+	if o.q != nil {
+		q.Set("q", o.q.String())
+	}
+	if o.geoQ != nil {
+		coordinates, err := json.Marshal(o.geoQ.Coordinates)
+		if err != nil {
+			return nil, err
+		}
+		q.Set("georel", o.geoQ.Georel)
+		q.Set("geometry", o.geoQ.Geometry)
+		q.Set("coordinates", string(coordinates))
+		if o.geoQ.GeoProperty != "" {
+			q.Set("geoproperty", o.geoQ.GeoProperty)
+		}
+	}
+	if o.scopeQ != nil {
+		q.Set("scopeQ", o.scopeQ.String())
+	}
+	if o.csf != nil {
+		q.Set("csf", o.csf.String())
+	}
+	if o.limit > 0 {
+		q.Set("limit", strconv.Itoa(o.limit))
+	}
+	if o.offset > 0 {
+		q.Set("offset", strconv.Itoa(o.offset))
+	}
+	if o.count {
+		q.Set("count", "true")
+	}
+	return q, nil
+}
+
+// QueryEntities returns the entities matching the options.
+// At least one of IDs, types, attrs, q, scopeQ, geoQ or csf must be provided.
+func (client *NgsiLdClient) QueryEntities(ctx context.Context, ldCtx *ldcontext.LdContext, opts ...QueryOptionFunc) ([]model.Entity, error) {
+	requestOptions, err := newQueryEntitiesOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entities, _, err := client.queryEntities(ctx, ldCtx, requestOptions)
+	return entities, err
+}
+
+// queryEntities runs a query, returning the total count of the matching
+// entities as well when the options ask for it, -1 otherwise
+func (client *NgsiLdClient) queryEntities(ctx context.Context, ldCtx *ldcontext.LdContext, requestOptions *queryEntitiesOptions) ([]model.Entity, int, error) {
+	headers := []requestHeader{}
+	link, err := contextLink(ldCtx)
+	if err != nil {
+		return nil, -1, err
+	}
+	if link != nil {
+		headers = append(headers, *link)
+	}
+	if requestOptions.geometryProperty != "" {
+		headers = append(headers, geoJSONResponse)
+	}
+
+	queryURL := strings.Join([]string{client.url, entitiesEndpoint}, "/")
+	req, err := client.newRequest(
+		ctx,
+		http.MethodGet,
+		queryURL,
+		nil,
+		headers...,
+	)
+	if err != nil {
+		return nil, -1, err
+	}
+	q, err := requestOptions.query()
+	if err != nil {
+		return nil, -1, errors.Wrap(ErrInvalidQueryOptions, err.Error())
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return nil, -1, errors.Wrap(err, "can't query Entities")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, -1, responseError(resp)
+	}
+
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, -1, errors.Wrap(err, "can't read Entities")
+	}
+
+	representation := entityRepresentation{
+		keyValues: requestOptions.keyValues,
+		geoJSON:   requestOptions.geometryProperty != "",
+	}
+	entities, err := representation.decodeList(bodyBytes)
+	if err != nil {
+		return nil, -1, errors.Wrap(err, "can't decode Entities")
+	}
+
+	// Brokers may not count the entities, the total is unknown then
+	total := -1
+	if count := resp.Header.Get(resultsCountHeader); requestOptions.count && count != "" {
+		total, err = strconv.Atoi(count)
+		if err != nil {
+			return nil, -1, errors.Wrapf(err, "invalid %s header", resultsCountHeader)
+		}
+	}
+	return entities, total, nil
+}
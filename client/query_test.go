@@ -0,0 +1,184 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phoops/ngsi-gold/client"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/phoops/ngsi-gold/query"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuerySuccess(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Assertion on the received request
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/entities", r.URL.Path)
+				assert.Equal(t, "Room,Office", r.URL.Query().Get("type"))
+				assert.Equal(t, "urn:room:.*", r.URL.Query().Get("idPattern"))
+				assert.Equal(t, `temperature>20;name=="Blue"`, r.URL.Query().Get("q"))
+				assert.Equal(t, "near;maxDistance==2000", r.URL.Query().Get("georel"))
+				assert.Equal(t, "Point", r.URL.Query().Get("geometry"))
+				assert.Equal(t, "[11.25,43.77]", r.URL.Query().Get("coordinates"))
+				assert.Equal(t, "/Italy/#", r.URL.Query().Get("scopeQ"))
+				assert.Equal(t, "10", r.URL.Query().Get("limit"))
+				assert.Equal(t, "20", r.URL.Query().Get("offset"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`[
+          {"id": "urn:room:1", "type": "Room", "temperature": {"type": "Property", "value": 21.5}},
+          {"id": "urn:room:2", "type": "Room", "temperature": {"type": "Property", "value": 22}}
+        ]`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entities, err := cli.QueryEntities(
+		context.Background(),
+		nil,
+		client.QuerySetTypes("Room", "Office"),
+		client.QuerySetIDPattern("urn:room:.*"),
+		client.QuerySetQ(query.And(query.Attr("temperature").Gt(20), query.Attr("name").Eq("Blue"))),
+		client.QuerySetGeoQuery(model.GeoQuery{
+			Geometry:    "Point",
+			Coordinates: []float64{11.25, 43.77},
+			Georel:      query.NearMaxDistance(2000),
+		}),
+		client.QuerySetScopeQ(query.Scope("Italy").Descendants()),
+		client.QuerySetLimit(10),
+		client.QuerySetOffset(20),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, entities, 2)
+	assert.Equal(t, "urn:room:2", entities[1].ID)
+	assert.Equal(t, 22.0, entities[1].Properties["temperature"][0].Value)
+}
+
+func TestQueryEmpty(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`[]`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entities, err := cli.QueryEntities(
+		context.Background(),
+		nil,
+		client.QuerySetAttrs("temperature"),
+	)
+	assert.NoError(t, err)
+	assert.Empty(t, entities)
+}
+
+func TestQueryScopeOnly(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/Italy/#", r.URL.Query().Get("scopeQ"))
+				assert.Empty(t, r.URL.Query().Get("type"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`[{"id": "urn:room:1", "type": "Room", "scope": "/Italy/Tuscany"}]`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entities, err := cli.QueryEntities(
+		context.Background(),
+		nil,
+		client.QuerySetScopeQ(query.Scope("Italy").Descendants()),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, entities, 1)
+	assert.Equal(t, []string{"/Italy/Tuscany"}, entities[0].Scope)
+}
+
+func TestQueryInvalidOptions(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("unused"),
+	)
+	assert.NoError(t, err)
+
+	// Unrestricted query
+	_, err = cli.QueryEntities(
+		context.Background(),
+		nil,
+		client.QuerySetLimit(10),
+	)
+	assert.ErrorIs(t, err, client.ErrInvalidQueryOptions)
+
+	_, err = cli.QueryEntities(
+		context.Background(),
+		nil,
+		client.QuerySetTypes("Room"),
+		client.QuerySetGeoQuery(model.GeoQuery{Geometry: "Point", Georel: "within"}),
+	)
+	assert.ErrorIs(t, err, client.ErrInvalidQueryOptions)
+
+	// Values the query language can't represent
+	_, err = cli.QueryEntities(
+		context.Background(),
+		nil,
+		client.QuerySetQ(query.Attr("color").In([]string{"red", "green"})),
+	)
+	assert.ErrorIs(t, err, client.ErrInvalidQueryOptions)
+	assert.ErrorContains(t, err, query.ErrInvalidValue.Error())
+}
+
+func TestQueryBadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, err := w.Write([]byte(`
+        {
+          "type": "https://uri.etsi.org/ngsi-ld/errors/BadRequestData",
+          "title": "Bad request data.",
+          "detail": "invalid q"
+        }
+        `))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	_, err = cli.QueryEntities(
+		context.Background(),
+		nil,
+		client.QuerySetQ(query.Raw("temperature>>20")),
+	)
+	assert.ErrorIs(t, err, client.ErrNgsiBadData)
+	assert.ErrorContains(t, err, "invalid q")
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/phoops/ngsi-gold/model"
+)
+
+// keyValuesGeoProperties lists the Attributes that the keyValues representation
+// carries as bare GeoJSON geometries
+var keyValuesGeoProperties = map[string]bool{
+	"location":         true,
+	"observationSpace": true,
+	"operationSpace":   true,
+}
+
+// entityMembers lists the members of an entity that are not Attributes
+var entityMembers = map[string]bool{
+	"id":         true,
+	"type":       true,
+	"scope":      true,
+	"@context":   true,
+	"createdAt":  true,
+	"modifiedAt": true,
+	"deletedAt":  true,
+}
+
+// normalizedAttributeTypes lists the types of the Attributes in the normalized representation
+var normalizedAttributeTypes = map[string]bool{
+	"Property":         true,
+	"Relationship":     true,
+	"GeoProperty":      true,
+	"LanguageProperty": true,
+	"VocabProperty":    true,
+	"JsonProperty":     true,
+	"ListProperty":     true,
+	"ListRelationship": true,
+}
+
+// isKeyValues tells whether the entity is in the keyValues representation,
+// that is some of its Attributes are not typed as in the normalized one
+func isKeyValues(b []byte) bool {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return false
+	}
+
+	for k, v := range fields {
+		if entityMembers[k] {
+			continue
+		}
+		if !isNormalizedAttribute(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNormalizedAttribute tells whether the Attribute is typed as in the normalized representation,
+// the Attributes with several instances are arrays of typed objects
+func isNormalizedAttribute(b json.RawMessage) bool {
+	type attribute struct {
+		Type string `json:"type"`
+	}
+
+	instances := []attribute{}
+	if err := json.Unmarshal(b, &instances); err != nil {
+		instance := attribute{}
+		if err := json.Unmarshal(b, &instance); err != nil {
+			return false
+		}
+		instances = []attribute{instance}
+	}
+	if len(instances) == 0 {
+		return false
+	}
+	for _, instance := range instances {
+		if !normalizedAttributeTypes[instance.Type] {
+			return false
+		}
+	}
+	return true
+}
+
+// entityRepresentation describes how the Context Broker serialized the entities of a response
+type entityRepresentation struct {
+	keyValues bool
+	geoJSON   bool
+}
+
+// decode restores the normalized representation of an entity, then decodes it
+func (r entityRepresentation) decode(b []byte) (*model.Entity, error) {
+	fields := map[string]json.RawMessage{}
+
+	if r.geoJSON {
+		// GeoJSON features carry the entity type and attributes in "properties"
+		feature := struct {
+			ID         string                     `json:"id"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		}{}
+		if err := json.Unmarshal(b, &feature); err != nil {
+			return nil, err
+		}
+		if feature.Properties != nil {
+			fields = feature.Properties
+		}
+		id, err := json.Marshal(feature.ID)
+		if err != nil {
+			return nil, err
+		}
+		fields["id"] = id
+	} else if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	delete(fields, "@context")
+
+	if r.keyValues {
+		// Simplified attributes lose their type: values become Properties,
+		// well-known geometries become GeoProperties.
+		// Null values can't be Properties, the attribute is left out.
+		for k, v := range fields {
+			if entityMembers[k] {
+				continue
+			}
+			if bytes.Equal(bytes.TrimSpace(v), []byte("null")) {
+				delete(fields, k)
+				continue
+			}
+			attributeType := "Property"
+			if keyValuesGeoProperties[k] {
+				attributeType = "GeoProperty"
+			}
+			normalized, err := json.Marshal(map[string]any{"type": attributeType, "value": v})
+			if err != nil {
+				return nil, err
+			}
+			fields[k] = normalized
+		}
+	}
+
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := model.Entity{}
+	if err := json.Unmarshal(normalized, &entity); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// decodeList decodes the entities of a query response
+func (r entityRepresentation) decodeList(b []byte) ([]model.Entity, error) {
+	items := []json.RawMessage{}
+
+	if r.geoJSON {
+		collection := struct {
+			Features []json.RawMessage `json:"features"`
+		}{}
+		if err := json.Unmarshal(b, &collection); err != nil {
+			return nil, err
+		}
+		items = collection.Features
+	} else if err := json.Unmarshal(b, &items); err != nil {
+		return nil, err
+	}
+
+	entities := make([]model.Entity, 0, len(items))
+	for _, item := range items {
+		entity, err := r.decode(item)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, *entity)
+	}
+	return entities, nil
+}
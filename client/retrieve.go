@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/pkg/errors"
+)
+
+type retrieveEntityOptions struct {
+	attrs            []string
+	sysAttrs         bool
+	keyValues        bool
+	geometryProperty string
+}
+
+type RetrieveOptionFunc func(*retrieveEntityOptions) error
+
+// RetrieveSetAttrs restricts the retrieved Attributes to the listed ones
+func RetrieveSetAttrs(attrs ...string) RetrieveOptionFunc {
+	return func(o *retrieveEntityOptions) error {
+		for _, a := range attrs {
+			if a == "" {
+				return errors.New("empty attribute name")
+			}
+		}
+		o.attrs = append(o.attrs, attrs...)
+		return nil
+	}
+}
+
+// RetrieveSetSysAttrs asks for the system generated attributes (createdAt, modifiedAt)
+var RetrieveSetSysAttrs RetrieveOptionFunc = func(o *retrieveEntityOptions) error {
+	o.sysAttrs = true
+	return nil
+}
+
+// RetrieveSetKeyValues asks for the simplified representation of the Entity.
+// Types of the Attributes are lost: they are decoded as Properties, except
+// for the well-known GeoProperties (location, observationSpace, operationSpace).
+var RetrieveSetKeyValues RetrieveOptionFunc = func(o *retrieveEntityOptions) error {
+	o.keyValues = true
+	return nil
+}
+
+// RetrieveSetGeometryProperty asks for the GeoJSON representation of the Entity,
+// using the named GeoProperty as geometry of the feature
+func RetrieveSetGeometryProperty(name string) RetrieveOptionFunc {
+	return func(o *retrieveEntityOptions) error {
+		if name == "" {
+			return errors.New("empty geometry property name")
+		}
+		o.geometryProperty = name
+		return nil
+	}
+}
+
+func (o *retrieveEntityOptions) query() url.Values {
+	q := url.Values{}
+	if len(o.attrs) > 0 {
+		q.Set("attrs", strings.Join(o.attrs, ","))
+	}
+
+	representation := []string{}
+	if o.sysAttrs {
+		representation = append(representation, "sysAttrs")
+	}
+	if o.keyValues {
+		representation = append(representation, "keyValues")
+	}
+	if len(representation) > 0 {
+		q.Set("options", strings.Join(representation, ","))
+	}
+
+	if o.geometryProperty != "" {
+		q.Set("geometryProperty", o.geometryProperty)
+	}
+	return q
+}
+
+func (client *NgsiLdClient) RetrieveEntity(ctx context.Context, ldCtx *ldcontext.LdContext, id string, opts ...RetrieveOptionFunc) (*model.Entity, error) {
+	if id == "" {
+		return nil, model.ErrEntityMissingID
+	}
+
+	requestOptions := &retrieveEntityOptions{}
+	for _, o := range opts {
+		err := o(requestOptions)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidRetrieveOptions, err.Error())
+		}
+	}
+
+	headers := []requestHeader{}
+	link, err := contextLink(ldCtx)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		headers = append(headers, *link)
+	}
+	if requestOptions.geometryProperty != "" {
+		headers = append(headers, geoJSONResponse)
+	}
+
+	retrieveURL := strings.Join([]string{client.url, entitiesEndpoint, url.PathEscape(id)}, "/")
+	req, err := client.newRequest(
+		ctx,
+		http.MethodGet,
+		retrieveURL,
+		nil,
+		headers...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = requestOptions.query().Encode()
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't retrieve Entity")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrapf(responseError(resp), "ID: %s", id)
+	}
+
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read Entity")
+	}
+
+	representation := entityRepresentation{
+		keyValues: requestOptions.keyValues,
+		geoJSON:   requestOptions.geometryProperty != "",
+	}
+	entity, err := representation.decode(bodyBytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't decode Entity %s", id)
+	}
+	return entity, nil
+}
@@ -0,0 +1,267 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsi-gold/client"
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/phoops/ngsi-gold/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetrieveSuccess(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Assertion on the received request
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/entities/urn:room:1", r.URL.Path)
+				assert.Equal(t, "application/json", r.Header.Get("Accept"))
+				assert.Equal(t, "temperature,wall", r.URL.Query().Get("attrs"))
+				assert.Equal(t, "sysAttrs", r.URL.Query().Get("options"))
+				assert.Empty(t, r.Header.Get("Link"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`{
+          "id": "urn:room:1",
+          "type": "Room",
+          "createdAt": "2024-05-01T10:00:00Z",
+          "temperature": {"type": "Property", "value": 21.5},
+          "wall": {"type": "Relationship", "object": "urn:wall:1"}
+        }`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entity, err := cli.RetrieveEntity(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		client.RetrieveSetAttrs("temperature", "wall"),
+		client.RetrieveSetSysAttrs,
+	)
+	assert.NoError(t, err)
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	assert.EqualValues(t, &model.Entity{
+		ID:        "urn:room:1",
+		Type:      "Room",
+		CreatedAt: &createdAt,
+		Properties: model.Properties{
+			"temperature": {{Value: 21.5}},
+		},
+		Relationships: model.Relationships{
+			"wall": {{Object: "urn:wall:1"}},
+		},
+	}, entity)
+}
+
+func TestRetrieveAttributeInstances(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`{
+          "id": "urn:room:1",
+          "type": "Room",
+          "scope": "/Italy/Tuscany",
+          "temperature": [
+            {"type": "Property", "value": 21.5},
+            {"type": "Property", "value": 21.7, "datasetId": "urn:dataset:1"}
+          ],
+          "name": {"type": "LanguageProperty", "languageMap": {"en": "Kitchen"}}
+        }`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entity, err := cli.RetrieveEntity(context.Background(), nil, "urn:room:1")
+	assert.NoError(t, err)
+	dataset := "urn:dataset:1"
+	assert.EqualValues(t, &model.Entity{
+		ID:    "urn:room:1",
+		Type:  "Room",
+		Scope: []string{"/Italy/Tuscany"},
+		Properties: model.Properties{
+			"temperature": {{Value: 21.5}, {Value: 21.7, DatasetID: &dataset}},
+		},
+		LanguageProperties: model.LanguageProperties{
+			"name": {{LanguageMap: map[string]string{"en": "Kitchen"}}},
+		},
+	}, entity)
+}
+
+func TestRetrieveKeyValues(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "keyValues", r.URL.Query().Get("options"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`{
+          "id": "urn:room:1",
+          "type": "Room",
+          "temperature": 21.5,
+          "name": null,
+          "location": {"type": "Point", "coordinates": [11.25, 43.77]}
+        }`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entity, err := cli.RetrieveEntity(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		client.RetrieveSetKeyValues,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 21.5, entity.Properties["temperature"][0].Value)
+	assert.NotContains(t, entity.Properties, "name")
+	assert.NotNil(t, entity.Location)
+	assert.Equal(t, []float64{11.25, 43.77}, entity.Location.Value.Point)
+}
+
+func TestRetrieveGeoJSON(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/geo+json", r.Header.Get("Accept"))
+				assert.Equal(t, "location", r.URL.Query().Get("geometryProperty"))
+
+				w.Header().Set("Content-Type", "application/geo+json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`{
+          "id": "urn:room:1",
+          "type": "Feature",
+          "geometry": {"type": "Point", "coordinates": [11.25, 43.77]},
+          "properties": {
+            "type": "Room",
+            "location": {"type": "GeoProperty", "value": {"type": "Point", "coordinates": [11.25, 43.77]}}
+          }
+        }`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entity, err := cli.RetrieveEntity(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		client.RetrieveSetGeometryProperty("location"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:room:1", entity.ID)
+	assert.Equal(t, "Room", entity.Type)
+	assert.NotNil(t, entity.Location)
+}
+
+func TestRetrieveContextLink(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t,
+					`<https://example.org/context.jsonld>; rel="http://www.w3.org/ns/json-ld#context"; type="application/ld+json"`,
+					r.Header.Get("Link"),
+				)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`{"id": "urn:room:1", "type": "Room"}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	_, err = cli.RetrieveEntity(
+		context.Background(),
+		&ldcontext.LdContext{"https://example.org/context.jsonld"},
+		"urn:room:1",
+	)
+	assert.NoError(t, err)
+
+	// Inline contexts can't travel in a Link header
+	_, err = cli.RetrieveEntity(
+		context.Background(),
+		&ldcontext.LdContext{map[string]any{"temperature": "https://example.org/temperature"}},
+		"urn:room:1",
+	)
+	assert.ErrorIs(t, err, client.ErrContextNotLinkable)
+}
+
+func TestRetrieveNotFound(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, err := w.Write([]byte(`
+        {
+          "type": "https://uri.etsi.org/ngsi-ld/errors/ResourceNotFound",
+          "title": "Resource not found.",
+          "detail": "urn:room:1"
+        }
+        `))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entity, err := cli.RetrieveEntity(
+		context.Background(),
+		nil,
+		"urn:room:1",
+	)
+	assert.Nil(t, entity)
+	assert.ErrorIs(t, err, client.ErrNgsiLdResourceNotFound)
+}
+
+func TestRetrieveInvalidOptions(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("unused"),
+	)
+	assert.NoError(t, err)
+
+	_, err = cli.RetrieveEntity(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		client.RetrieveSetAttrs(""),
+	)
+	assert.ErrorIs(t, err, client.ErrInvalidRetrieveOptions)
+}
@@ -0,0 +1,291 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/pkg/errors"
+)
+
+const subscriptionsEndpoint string = "ngsi-ld/v1/subscriptions"
+
+type querySubscriptionsOptions struct {
+	limit  int
+	offset int
+}
+
+type SubscriptionsOptionFunc func(*querySubscriptionsOptions) error
+
+// SubscriptionsSetLimit caps the number of returned subscriptions
+func SubscriptionsSetLimit(limit int) SubscriptionsOptionFunc {
+	return func(o *querySubscriptionsOptions) error {
+		if limit <= 0 {
+			return errors.New("limit must be positive")
+		}
+		o.limit = limit
+		return nil
+	}
+}
+
+// SubscriptionsSetOffset skips the first subscriptions of the results
+func SubscriptionsSetOffset(offset int) SubscriptionsOptionFunc {
+	return func(o *querySubscriptionsOptions) error {
+		if offset < 0 {
+			return errors.New("offset can't be negative")
+		}
+		o.offset = offset
+		return nil
+	}
+}
+
+// CreateSubscription registers the subscription, returning the ID it is known by
+func (client *NgsiLdClient) CreateSubscription(ctx context.Context, ldCtx *ldcontext.LdContext, subscription *model.Subscription) (string, error) {
+	// Set default context whenever missing
+	if ldCtx == nil {
+		ldCtx = &ldcontext.DefaultContext
+	}
+
+	// Validate subscription to be created before contacting the server
+	err := subscription.Validate(true)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid Subscription")
+	}
+
+	createURL := strings.Join([]string{client.url, subscriptionsEndpoint}, "/")
+	createRequest, bodyHeaders, err := client.addContext(subscription, ldCtx)
+	if err != nil {
+		return "", err
+	}
+	createRequestBody, err := json.Marshal(&createRequest)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := client.newRequest(
+		ctx,
+		http.MethodPost,
+		createURL,
+		bytes.NewBuffer(createRequestBody),
+		bodyHeaders...,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "can't create Subscription")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", errors.Wrapf(responseError(resp), "ID: %s", subscription.ID)
+	}
+
+	// The Context Broker tells where the subscription lives
+	if location := resp.Header.Get("Location"); location != "" {
+		id, err := url.PathUnescape(path.Base(location))
+		if err == nil {
+			return id, nil
+		}
+	}
+	return subscription.ID, nil
+}
+
+// RetrieveSubscription returns the subscription having the ID
+func (client *NgsiLdClient) RetrieveSubscription(ctx context.Context, ldCtx *ldcontext.LdContext, id string) (*model.Subscription, error) {
+	if id == "" {
+		return nil, errors.Wrap(ErrInvalidSubscriptionOptions, "empty subscription ID")
+	}
+
+	retrieveURL := strings.Join([]string{client.url, subscriptionsEndpoint, url.PathEscape(id)}, "/")
+	bodyBytes, err := client.getSubscriptions(ctx, ldCtx, retrieveURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "ID: %s", id)
+	}
+
+	subscription := model.Subscription{}
+	err = json.Unmarshal(bodyBytes, &subscription)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't decode Subscription %s", id)
+	}
+	return &subscription, nil
+}
+
+// QuerySubscriptions lists the subscriptions known by the Context Broker
+func (client *NgsiLdClient) QuerySubscriptions(ctx context.Context, ldCtx *ldcontext.LdContext, opts ...SubscriptionsOptionFunc) ([]model.Subscription, error) {
+	requestOptions := &querySubscriptionsOptions{}
+	for _, o := range opts {
+		err := o(requestOptions)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidSubscriptionOptions, err.Error())
+		}
+	}
+
+	q := url.Values{}
+	if requestOptions.limit > 0 {
+		q.Set("limit", strconv.Itoa(requestOptions.limit))
+	}
+	if requestOptions.offset > 0 {
+		q.Set("offset", strconv.Itoa(requestOptions.offset))
+	}
+
+	queryURL := strings.Join([]string{client.url, subscriptionsEndpoint}, "/")
+	if len(q) > 0 {
+		queryURL += "?" + q.Encode()
+	}
+	bodyBytes, err := client.getSubscriptions(ctx, ldCtx, queryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := []model.Subscription{}
+	err = json.Unmarshal(bodyBytes, &subscriptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't decode Subscriptions")
+	}
+	return subscriptions, nil
+}
+
+func (client *NgsiLdClient) getSubscriptions(ctx context.Context, ldCtx *ldcontext.LdContext, subscriptionsURL string) ([]byte, error) {
+	headers := []requestHeader{}
+	link, err := contextLink(ldCtx)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		headers = append(headers, *link)
+	}
+
+	req, err := client.newRequest(
+		ctx,
+		http.MethodGet,
+		subscriptionsURL,
+		nil,
+		headers...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't retrieve Subscriptions")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read Subscriptions")
+	}
+	return bodyBytes, nil
+}
+
+// UpdateSubscription modifies the subscription with the fields set in the fragment.
+// The fragment is not validated, as it can lack mandatory fields. The members set by
+// the Context Broker, like the ID and the status, are not sent, so a retrieved
+// subscription can be modified and sent back.
+func (client *NgsiLdClient) UpdateSubscription(ctx context.Context, ldCtx *ldcontext.LdContext, id string, fragment *model.Subscription) error {
+	// Set default context whenever missing
+	if ldCtx == nil {
+		ldCtx = &ldcontext.DefaultContext
+	}
+
+	if id == "" {
+		return errors.Wrap(ErrInvalidSubscriptionOptions, "empty subscription ID")
+	}
+	if fragment == nil {
+		return errors.Wrap(ErrInvalidSubscriptionOptions, "missing subscription fragment")
+	}
+
+	updateRequest, bodyHeaders, err := client.addContext(writableSubscription(fragment), ldCtx)
+	if err != nil {
+		return err
+	}
+	updateRequestBody, err := json.Marshal(&updateRequest)
+	if err != nil {
+		return err
+	}
+
+	updateURL := strings.Join([]string{client.url, subscriptionsEndpoint, url.PathEscape(id)}, "/")
+	req, err := client.newRequest(
+		ctx,
+		http.MethodPatch,
+		updateURL,
+		bytes.NewBuffer(updateRequestBody),
+		bodyHeaders...,
+	)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "can't update Subscription")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return errors.Wrapf(responseError(resp), "ID: %s", id)
+}
+
+// DeleteSubscription removes the subscription, no more notifications are sent
+func (client *NgsiLdClient) DeleteSubscription(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.Wrap(ErrInvalidSubscriptionOptions, "empty subscription ID")
+	}
+
+	deleteURL := strings.Join([]string{client.url, subscriptionsEndpoint, url.PathEscape(id)}, "/")
+	req, err := client.newRequest(
+		ctx,
+		http.MethodDelete,
+		deleteURL,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "can't delete Subscription")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return errors.Wrapf(responseError(resp), "ID: %s", id)
+}
+
+// writableSubscription copies the subscription without the members set by the Context Broker
+func writableSubscription(s *model.Subscription) *model.Subscription {
+	writable := *s
+	writable.ID = ""
+	writable.Status = ""
+	if s.Notification != nil {
+		notification := *s.Notification
+		notification.Status = ""
+		notification.TimesSent = 0
+		notification.LastNotification = nil
+		notification.LastFailure = nil
+		notification.LastSuccess = nil
+		writable.Notification = &notification
+	}
+	return &writable
+}
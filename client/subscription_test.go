@@ -0,0 +1,235 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsi-gold/client"
+	"github.com/phoops/ngsi-gold/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSubscription() *model.Subscription {
+	return &model.Subscription{
+		Entities:          []model.EntitySelector{{Type: "Room"}},
+		WatchedAttributes: []string{"temperature"},
+		Notification: &model.NotificationParams{
+			Endpoint: model.Endpoint{URI: "http://receiver:8080/notify"},
+		},
+	}
+}
+
+func TestCreateSubscriptionSuccess(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Assertion on the received request
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/subscriptions", r.URL.Path)
+				assert.Equal(t, "application/ld+json", r.Header.Get("Content-Type"))
+
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				body := map[string]any{}
+				assert.NoError(t, json.Unmarshal(b, &body))
+				assert.Contains(t, body, "@context")
+				assert.Equal(t, "Subscription", body["type"])
+
+				w.Header().Set("Location", "/ngsi-ld/v1/subscriptions/urn:ngsi-ld:Subscription:1")
+				w.WriteHeader(http.StatusCreated)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	id, err := cli.CreateSubscription(context.Background(), nil, testSubscription())
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:ngsi-ld:Subscription:1", id)
+}
+
+func TestCreateSubscriptionValidation(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("unused"),
+	)
+	assert.NoError(t, err)
+
+	s := testSubscription()
+	s.Notification = nil
+	_, err = cli.CreateSubscription(context.Background(), nil, s)
+	assert.ErrorIs(t, err, model.ErrSubscriptionMissingNotification)
+	assert.ErrorContains(t, err, "invalid Subscription")
+}
+
+func TestRetrieveSubscription(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/subscriptions/urn:ngsi-ld:Subscription:1", r.URL.Path)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`{
+          "id": "urn:ngsi-ld:Subscription:1",
+          "type": "Subscription",
+          "entities": [{"type": "Room"}],
+          "watchedAttributes": ["temperature"],
+          "isActive": true,
+          "status": "active",
+          "notification": {
+            "format": "normalized",
+            "endpoint": {"uri": "http://receiver:8080/notify", "accept": "application/json"},
+            "timesSent": 3,
+            "lastNotification": "2023-02-13T11:30:40.123Z"
+          }
+        }`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	s, err := cli.RetrieveSubscription(context.Background(), nil, "urn:ngsi-ld:Subscription:1")
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:ngsi-ld:Subscription:1", s.ID)
+	assert.Equal(t, "active", s.Status)
+	assert.True(t, *s.IsActive)
+	assert.Equal(t, 3, s.Notification.TimesSent)
+	assert.NotNil(t, s.Notification.LastNotification)
+}
+
+func TestQuerySubscriptions(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/subscriptions", r.URL.Path)
+				assert.Equal(t, "5", r.URL.Query().Get("limit"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`[
+          {"id": "urn:ngsi-ld:Subscription:1", "type": "Subscription", "entities": [{"type": "Room"}], "notification": {"endpoint": {"uri": "http://receiver:8080/notify"}}},
+          {"id": "urn:ngsi-ld:Subscription:2", "type": "Subscription", "watchedAttributes": ["speed"], "notification": {"endpoint": {"uri": "http://receiver:8080/notify"}}}
+        ]`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	subscriptions, err := cli.QuerySubscriptions(context.Background(), nil, client.SubscriptionsSetLimit(5))
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 2)
+	assert.Equal(t, []string{"speed"}, subscriptions[1].WatchedAttributes)
+}
+
+func TestUpdateSubscription(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPatch, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/subscriptions/urn:ngsi-ld:Subscription:1", r.URL.Path)
+
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				body := map[string]any{}
+				assert.NoError(t, json.Unmarshal(b, &body))
+				assert.Equal(t, false, body["isActive"])
+				assert.NotContains(t, body, "notification")
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	inactive := false
+	err = cli.UpdateSubscription(context.Background(), nil, "urn:ngsi-ld:Subscription:1", &model.Subscription{IsActive: &inactive})
+	assert.NoError(t, err)
+}
+
+func TestUpdateSubscriptionReadOnlyMembers(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				body := map[string]any{}
+				assert.NoError(t, json.Unmarshal(b, &body))
+				assert.NotContains(t, body, "id")
+				assert.NotContains(t, body, "status")
+				assert.Equal(t, map[string]any{
+					"format":   "keyValues",
+					"endpoint": map[string]any{"uri": "http://example.org/notify"},
+				}, body["notification"])
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	lastNotification := time.Now()
+	retrieved := &model.Subscription{
+		ID:     "urn:ngsi-ld:Subscription:1",
+		Status: "active",
+		Notification: &model.NotificationParams{
+			Format:           model.NotificationFormatKeyValues,
+			Endpoint:         model.Endpoint{URI: "http://example.org/notify"},
+			Status:           "ok",
+			TimesSent:        3,
+			LastNotification: &lastNotification,
+		},
+	}
+	err = cli.UpdateSubscription(context.Background(), nil, retrieved.ID, retrieved)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, retrieved.Notification.TimesSent)
+
+	err = cli.UpdateSubscription(context.Background(), nil, retrieved.ID, nil)
+	assert.ErrorIs(t, err, client.ErrInvalidSubscriptionOptions)
+}
+
+func TestDeleteSubscriptionNotFound(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodDelete, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/subscriptions/urn:ngsi-ld:Subscription:1", r.URL.Path)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, err := w.Write([]byte(`{"type": "https://uri.etsi.org/ngsi-ld/errors/ResourceNotFound", "title": "Subscription not found"}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.DeleteSubscription(context.Background(), "urn:ngsi-ld:Subscription:1")
+	assert.ErrorIs(t, err, client.ErrNgsiLdResourceNotFound)
+}
@@ -0,0 +1,361 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/pkg/errors"
+)
+
+const temporalEntitiesEndpoint string = "ngsi-ld/v1/temporal/entities"
+
+type temporalOptions struct {
+	// Filters on the entities, as in QueryEntities
+	query queryEntitiesOptions
+
+	temporalQ *model.TemporalQuery
+	lastN     int
+
+	// Aggregated representation
+	aggrMethods        []string
+	aggrPeriodDuration string
+
+	// Representation of the temporal entities, set by the operation
+	representation string
+}
+
+const (
+	temporalValuesRepresentation   = "temporalValues"
+	aggregatedValuesRepresentation = "aggregatedValues"
+)
+
+// ISO 8601 duration, e.g. P1D or PT15M
+var aggrPeriodDurationRegex = regexp.MustCompile(`^P(\d+Y)?(\d+M)?(\d+W)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
+
+type TemporalOptionFunc func(*temporalOptions) error
+
+// TemporalSetQuery selects the entities and Attributes as QueryEntities does,
+// the options about the representation of the entities are not allowed
+func TemporalSetQuery(opts ...QueryOptionFunc) TemporalOptionFunc {
+	return func(o *temporalOptions) error {
+		for _, opt := range opts {
+			err := opt(&o.query)
+			if err != nil {
+				return err
+			}
+		}
+		if o.query.keyValues || o.query.geometryProperty != "" {
+			return errors.New("keyValues and GeoJSON representations don't apply to temporal entities")
+		}
+		return nil
+	}
+}
+
+// TemporalSetTemporalQuery selects the instances of the Attributes by time
+func TemporalSetTemporalQuery(temporalQ model.TemporalQuery) TemporalOptionFunc {
+	return func(o *temporalOptions) error {
+		err := temporalQ.Validate(true)
+		if err != nil {
+			return err
+		}
+		o.temporalQ = &temporalQ
+		return nil
+	}
+}
+
+// TemporalSetBefore selects the instances preceding the time
+func TemporalSetBefore(timeAt time.Time) TemporalOptionFunc {
+	return TemporalSetTemporalQuery(model.TemporalQuery{Timerel: model.TimerelBefore, TimeAt: timeAt})
+}
+
+// TemporalSetAfter selects the instances following the time
+func TemporalSetAfter(timeAt time.Time) TemporalOptionFunc {
+	return TemporalSetTemporalQuery(model.TemporalQuery{Timerel: model.TimerelAfter, TimeAt: timeAt})
+}
+
+// TemporalSetBetween selects the instances in the time interval
+func TemporalSetBetween(timeAt, endTimeAt time.Time) TemporalOptionFunc {
+	return TemporalSetTemporalQuery(model.TemporalQuery{Timerel: model.TimerelBetween, TimeAt: timeAt, EndTimeAt: &endTimeAt})
+}
+
+// TemporalSetTimeProperty selects the time property the temporal query applies to,
+// one of observedAt (default), createdAt or modifiedAt.
+// It must follow the option setting the temporal query.
+func TemporalSetTimeProperty(timeProperty string) TemporalOptionFunc {
+	return func(o *temporalOptions) error {
+		if o.temporalQ == nil {
+			return errors.New("time property set without temporal query")
+		}
+		temporalQ := *o.temporalQ
+		temporalQ.TimeProperty = timeProperty
+		return TemporalSetTemporalQuery(temporalQ)(o)
+	}
+}
+
+// TemporalSetLastN returns only the last n instances of each Attribute
+func TemporalSetLastN(n int) TemporalOptionFunc {
+	return func(o *temporalOptions) error {
+		if n <= 0 {
+			return errors.New("lastN must be positive")
+		}
+		o.lastN = n
+		return nil
+	}
+}
+
+// TemporalSetAggrMethods selects the aggregation methods applied to the Attributes,
+// see the model.Aggr constants. Used only by the aggregated operations.
+func TemporalSetAggrMethods(methods ...string) TemporalOptionFunc {
+	return func(o *temporalOptions) error {
+		for _, method := range methods {
+			switch method {
+			case model.AggrTotalCount, model.AggrDistinctCount, model.AggrSum, model.AggrAvg,
+				model.AggrMin, model.AggrMax, model.AggrStdDev, model.AggrSumSq:
+			default:
+				return errors.Errorf("unknown aggregation method %q", method)
+			}
+		}
+		o.aggrMethods = append(o.aggrMethods, methods...)
+		return nil
+	}
+}
+
+// TemporalSetAggrPeriodDuration sets the period the values are aggregated over,
+// as ISO 8601 duration (e.g. P1D). Used only by the aggregated operations.
+func TemporalSetAggrPeriodDuration(duration string) TemporalOptionFunc {
+	return func(o *temporalOptions) error {
+		if duration == "P" || strings.HasSuffix(duration, "T") || !aggrPeriodDurationRegex.MatchString(duration) {
+			return errors.Errorf("invalid ISO 8601 duration %q", duration)
+		}
+		o.aggrPeriodDuration = duration
+		return nil
+	}
+}
+
+func newTemporalOptions(opts []TemporalOptionFunc, representation string) (*temporalOptions, error) {
+	requestOptions := &temporalOptions{representation: representation}
+	for _, o := range opts {
+		err := o(requestOptions)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidTemporalOptions, err.Error())
+		}
+	}
+
+	aggregated := representation == aggregatedValuesRepresentation
+	if aggregated && len(requestOptions.aggrMethods) == 0 {
+		return nil, errors.Wrap(ErrInvalidTemporalOptions, "aggregation methods are required")
+	}
+	if !aggregated && (len(requestOptions.aggrMethods) > 0 || requestOptions.aggrPeriodDuration != "") {
+		return nil, errors.Wrap(ErrInvalidTemporalOptions, "aggregation options apply only to the aggregated representation")
+	}
+	return requestOptions, nil
+}
+
+func (o *temporalOptions) values() (url.Values, error) {
+	q, err := o.query.query()
+	if err != nil {
+		return nil, err
+	}
+
+	if o.temporalQ != nil {
+		q.Set("timerel", o.temporalQ.Timerel)
+		q.Set("timeAt", o.temporalQ.TimeAt.UTC().Format(model.TimeRFC3339Micro))
+		if o.temporalQ.EndTimeAt != nil {
+			q.Set("endTimeAt", o.temporalQ.EndTimeAt.UTC().Format(model.TimeRFC3339Micro))
+		}
+		if o.temporalQ.TimeProperty != "" {
+			q.Set("timeproperty", o.temporalQ.TimeProperty)
+		}
+	}
+	if o.lastN > 0 {
+		q.Set("lastN", strconv.Itoa(o.lastN))
+	}
+
+	if o.representation != "" {
+		representation := []string{o.representation}
+		if q.Get("options") != "" {
+			representation = append(representation, q.Get("options"))
+		}
+		q.Set("options", strings.Join(representation, ","))
+	}
+	if len(o.aggrMethods) > 0 {
+		q.Set("aggrMethods", strings.Join(o.aggrMethods, ","))
+	}
+	if o.aggrPeriodDuration != "" {
+		q.Set("aggrPeriodDuration", o.aggrPeriodDuration)
+	}
+	return q, nil
+}
+
+// RetrieveTemporalEntity returns the evolution in time of the Entity
+func (client *NgsiLdClient) RetrieveTemporalEntity(ctx context.Context, ldCtx *ldcontext.LdContext, id string, opts ...TemporalOptionFunc) (*model.TemporalEntity, error) {
+	entity := model.TemporalEntity{}
+	err := client.retrieveTemporal(ctx, ldCtx, id, opts, "", &entity)
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// QueryTemporalEntities returns the evolution in time of the entities matching the options.
+// A temporal query is mandatory, and the entities must be selected as in QueryEntities.
+func (client *NgsiLdClient) QueryTemporalEntities(ctx context.Context, ldCtx *ldcontext.LdContext, opts ...TemporalOptionFunc) ([]model.TemporalEntity, error) {
+	entities := []model.TemporalEntity{}
+	err := client.queryTemporal(ctx, ldCtx, opts, "", &entities)
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// RetrieveTemporalEntityValues returns the evolution in time of the Entity
+// in the simplified representation, holding only values and timestamps
+func (client *NgsiLdClient) RetrieveTemporalEntityValues(ctx context.Context, ldCtx *ldcontext.LdContext, id string, opts ...TemporalOptionFunc) (*model.TemporalValuesEntity, error) {
+	entity := model.TemporalValuesEntity{}
+	err := client.retrieveTemporal(ctx, ldCtx, id, opts, temporalValuesRepresentation, &entity)
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// QueryTemporalEntitiesValues returns the evolution in time of the entities matching
+// the options in the simplified representation, see QueryTemporalEntities
+func (client *NgsiLdClient) QueryTemporalEntitiesValues(ctx context.Context, ldCtx *ldcontext.LdContext, opts ...TemporalOptionFunc) ([]model.TemporalValuesEntity, error) {
+	entities := []model.TemporalValuesEntity{}
+	err := client.queryTemporal(ctx, ldCtx, opts, temporalValuesRepresentation, &entities)
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// RetrieveAggregatedTemporalEntity returns the Attributes of the Entity aggregated over time.
+// The aggregation methods are mandatory, the period defaults to the whole time interval.
+func (client *NgsiLdClient) RetrieveAggregatedTemporalEntity(ctx context.Context, ldCtx *ldcontext.LdContext, id string, opts ...TemporalOptionFunc) (*model.AggregatedTemporalEntity, error) {
+	entity := model.AggregatedTemporalEntity{}
+	err := client.retrieveTemporal(ctx, ldCtx, id, opts, aggregatedValuesRepresentation, &entity)
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// QueryAggregatedTemporalEntities returns the Attributes of the entities matching the options
+// aggregated over time, see QueryTemporalEntities and RetrieveAggregatedTemporalEntity
+func (client *NgsiLdClient) QueryAggregatedTemporalEntities(ctx context.Context, ldCtx *ldcontext.LdContext, opts ...TemporalOptionFunc) ([]model.AggregatedTemporalEntity, error) {
+	entities := []model.AggregatedTemporalEntity{}
+	err := client.queryTemporal(ctx, ldCtx, opts, aggregatedValuesRepresentation, &entities)
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (client *NgsiLdClient) retrieveTemporal(ctx context.Context, ldCtx *ldcontext.LdContext, id string, opts []TemporalOptionFunc, representation string, entity any) error {
+	if id == "" {
+		return model.ErrEntityMissingID
+	}
+
+	requestOptions, err := newTemporalOptions(opts, representation)
+	if err != nil {
+		return err
+	}
+
+	retrieveURL := strings.Join([]string{client.url, temporalEntitiesEndpoint, url.PathEscape(id)}, "/")
+	bodyBytes, err := client.getTemporal(ctx, ldCtx, retrieveURL, requestOptions)
+	if err != nil {
+		return errors.Wrapf(err, "ID: %s", id)
+	}
+
+	err = json.Unmarshal(bodyBytes, entity)
+	if err != nil {
+		return errors.Wrapf(err, "can't decode temporal Entity %s", id)
+	}
+	return nil
+}
+
+func (client *NgsiLdClient) queryTemporal(ctx context.Context, ldCtx *ldcontext.LdContext, opts []TemporalOptionFunc, representation string, entities any) error {
+	requestOptions, err := newTemporalQueryOptions(opts, representation)
+	if err != nil {
+		return err
+	}
+
+	queryURL := strings.Join([]string{client.url, temporalEntitiesEndpoint}, "/")
+	bodyBytes, err := client.getTemporal(ctx, ldCtx, queryURL, requestOptions)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(bodyBytes, entities)
+	if err != nil {
+		return errors.Wrap(err, "can't decode temporal Entities")
+	}
+	return nil
+}
+
+func newTemporalQueryOptions(opts []TemporalOptionFunc, representation string) (*temporalOptions, error) {
+	requestOptions, err := newTemporalOptions(opts, representation)
+	if err != nil {
+		return nil, err
+	}
+	if requestOptions.temporalQ == nil {
+		return nil, errors.Wrap(ErrInvalidTemporalOptions, "temporal query is required")
+	}
+	if !requestOptions.query.hasSelector() {
+		return nil, errors.Wrap(ErrInvalidTemporalOptions, errMissingQuerySelector.Error())
+	}
+	return requestOptions, nil
+}
+
+func (client *NgsiLdClient) getTemporal(ctx context.Context, ldCtx *ldcontext.LdContext, temporalURL string, requestOptions *temporalOptions) ([]byte, error) {
+	headers := []requestHeader{}
+	link, err := contextLink(ldCtx)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		headers = append(headers, *link)
+	}
+
+	req, err := client.newRequest(
+		ctx,
+		http.MethodGet,
+		temporalURL,
+		nil,
+		headers...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	q, err := requestOptions.values()
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidTemporalOptions, err.Error())
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't retrieve temporal Entities")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read temporal Entities")
+	}
+	return bodyBytes, nil
+}
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/pkg/errors"
+)
+
+// CreateOrUpdateTemporalEntity stores the instances of the temporal Entity,
+// creating it if missing. It returns true when the Entity has been created.
+func (client *NgsiLdClient) CreateOrUpdateTemporalEntity(ctx context.Context, ldCtx *ldcontext.LdContext, entity *model.TemporalEntity) (bool, error) {
+	// Validate entity to be stored before contacting the server
+	if entity == nil {
+		return false, model.ErrEntityMissingID
+	}
+	err := entity.Validate(true)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid temporal Entity")
+	}
+
+	createURL := strings.Join([]string{client.url, temporalEntitiesEndpoint}, "/")
+	resp, err := client.sendTemporal(ctx, ldCtx, http.MethodPost, createURL, entity, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "can't store temporal Entity")
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return true, nil
+	case http.StatusNoContent:
+		return false, nil
+	}
+	return false, errors.Wrapf(responseError(resp), "ID: %s", entity.ID)
+}
+
+// AppendTemporalAttributes adds the instances of the fragment to the temporal Entity
+func (client *NgsiLdClient) AppendTemporalAttributes(ctx context.Context, ldCtx *ldcontext.LdContext, id string, attrs *model.TemporalEntityFragment) (*UpdateResult, error) {
+	if id == "" {
+		return nil, model.ErrEntityMissingID
+	}
+	if attrs == nil {
+		return nil, errors.Wrap(model.ErrEntityFragmentEmpty, "invalid Attributes")
+	}
+	err := attrs.Validate(true)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Attributes")
+	}
+
+	attrsURL := strings.Join([]string{client.url, temporalEntitiesEndpoint, url.PathEscape(id), entityAttrsPath}, "/")
+	resp, err := client.sendTemporal(ctx, ldCtx, http.MethodPost, attrsURL, attrs, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't append temporal Attributes")
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return &UpdateResult{Updated: attrs.Names()}, nil
+	case http.StatusMultiStatus:
+		bodyBytes, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't read update result")
+		}
+		result := UpdateResult{}
+		err = json.Unmarshal(bodyBytes, &result)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't decode update result: %s", string(bodyBytes))
+		}
+		return &result, nil
+	}
+	return nil, errors.Wrapf(responseError(resp), "ID: %s", id)
+}
+
+// ModifyAttributeInstance replaces the instance of the Attribute, identified by its instanceId,
+// with the values of attr
+func (client *NgsiLdClient) ModifyAttributeInstance(ctx context.Context, ldCtx *ldcontext.LdContext, id, attrID, instanceID string, attr model.Attribute) error {
+	err := checkAttributeInstance(id, attrID, instanceID)
+	if err != nil {
+		return err
+	}
+	if nilAttribute(attr) {
+		return errors.Wrap(ErrInvalidAttributeOptions, "missing Attribute")
+	}
+	err = attr.Validate(true)
+	if err != nil {
+		return errors.Wrap(err, "invalid Attribute")
+	}
+
+	instanceURL := strings.Join([]string{
+		client.url, temporalEntitiesEndpoint, url.PathEscape(id), entityAttrsPath, url.PathEscape(attrID), url.PathEscape(instanceID),
+	}, "/")
+	resp, err := client.sendTemporal(ctx, ldCtx, http.MethodPatch, instanceURL, attr, &instanceID)
+	if err != nil {
+		return errors.Wrap(err, "can't modify Attribute instance")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return errors.Wrapf(responseError(resp), "ID: %s, Attribute: %s, instance: %s", id, attrID, instanceID)
+}
+
+// DeleteAttributeInstance removes the instance of the Attribute, identified by its instanceId
+func (client *NgsiLdClient) DeleteAttributeInstance(ctx context.Context, ldCtx *ldcontext.LdContext, id, attrID, instanceID string) error {
+	err := checkAttributeInstance(id, attrID, instanceID)
+	if err != nil {
+		return err
+	}
+
+	headers := []requestHeader{}
+	link, err := contextLink(ldCtx)
+	if err != nil {
+		return err
+	}
+	if link != nil {
+		headers = append(headers, *link)
+	}
+
+	instanceURL := strings.Join([]string{
+		client.url, temporalEntitiesEndpoint, url.PathEscape(id), entityAttrsPath, url.PathEscape(attrID), url.PathEscape(instanceID),
+	}, "/")
+	req, err := client.newRequest(
+		ctx,
+		http.MethodDelete,
+		instanceURL,
+		nil,
+		headers...,
+	)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "can't delete Attribute instance")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return errors.Wrapf(responseError(resp), "ID: %s, Attribute: %s, instance: %s", id, attrID, instanceID)
+}
+
+func checkAttributeInstance(id, attrID, instanceID string) error {
+	if id == "" {
+		return model.ErrEntityMissingID
+	}
+	if attrID == "" {
+		return errors.Wrap(ErrInvalidAttributeOptions, "empty attribute name")
+	}
+	if instanceID == "" {
+		return errors.Wrap(ErrInvalidAttributeOptions, "empty instanceId")
+	}
+	return nil
+}
+
+// sendTemporal sends the payload with its @context to the temporal endpoint.
+// When instanceID is given the payload can only carry the same instanceId.
+func (client *NgsiLdClient) sendTemporal(ctx context.Context, ldCtx *ldcontext.LdContext, method, targetURL string, payload json.Marshaler, instanceID *string) (*http.Response, error) {
+	// Set default context whenever missing
+	if ldCtx == nil {
+		ldCtx = &ldcontext.DefaultContext
+	}
+
+	request, bodyHeaders, err := client.addContext(payload, ldCtx)
+	if err != nil {
+		return nil, err
+	}
+	if instanceID != nil {
+		expected, err := json.Marshal(*instanceID)
+		if err != nil {
+			return nil, err
+		}
+		if current, ok := request["instanceId"]; ok && !bytes.Equal(current, expected) {
+			return nil, errors.Wrap(ErrInvalidAttributeOptions, "instanceId differs from the one of the Attribute")
+		}
+	}
+	requestBody, err := json.Marshal(&request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := client.newRequest(
+		ctx,
+		method,
+		targetURL,
+		bytes.NewBuffer(requestBody),
+		bodyHeaders...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.c.Do(req)
+}
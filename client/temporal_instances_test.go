@@ -0,0 +1,197 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsi-gold/client"
+	"github.com/phoops/ngsi-gold/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOrUpdateTemporalEntity(t *testing.T) {
+	first := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	second := time.Date(2022, 3, 1, 11, 0, 0, 0, time.UTC)
+
+	status := http.StatusCreated
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Assertion on the received request
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/temporal/entities", r.URL.Path)
+				assert.Equal(t, "application/ld+json", r.Header.Get("Content-Type"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, `{
+          "@context": ["https://uri.etsi.org/ngsi-ld/v1/ngsi-ld-core-context.jsonld"],
+          "id": "urn:room:1",
+          "type": "Room",
+          "temperature": [
+            {"type": "Property", "value": 21.5, "observedAt": "2022-03-01T10:00:00Z"},
+            {"type": "Property", "value": 22.5, "observedAt": "2022-03-01T11:00:00Z"}
+          ]
+        }`, string(body))
+
+				w.WriteHeader(status)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entity := &model.TemporalEntity{
+		ID:   "urn:room:1",
+		Type: "Room",
+		Properties: model.TemporalProperties{
+			"temperature": {
+				{Value: 21.5, ObservedAt: &first},
+				{Value: 22.5, ObservedAt: &second},
+			},
+		},
+	}
+
+	created, err := cli.CreateOrUpdateTemporalEntity(context.Background(), nil, entity)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	status = http.StatusNoContent
+	created, err = cli.CreateOrUpdateTemporalEntity(context.Background(), nil, entity)
+	assert.NoError(t, err)
+	assert.False(t, created)
+}
+
+func TestCreateOrUpdateTemporalEntityInvalid(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("http://localhost:1026"),
+	)
+	assert.NoError(t, err)
+
+	_, err = cli.CreateOrUpdateTemporalEntity(context.Background(), nil, &model.TemporalEntity{Type: "Room"})
+	assert.ErrorIs(t, err, model.ErrEntityMissingID)
+}
+
+func TestAppendTemporalAttributes(t *testing.T) {
+	observedAt := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/temporal/entities/urn:room:1/attrs", r.URL.Path)
+
+				body := map[string]json.RawMessage{}
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Contains(t, body, "temperature")
+				assert.Contains(t, body, "wall")
+				assert.NotContains(t, body, "id")
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	result, err := cli.AppendTemporalAttributes(context.Background(), nil, "urn:room:1", &model.TemporalEntityFragment{
+		Properties: model.TemporalProperties{
+			"temperature": {{Value: 21.5, ObservedAt: &observedAt}},
+		},
+		Relationships: model.TemporalRelationships{
+			"wall": {{Object: "urn:wall:1", ObservedAt: &observedAt}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &client.UpdateResult{Updated: []string{"temperature", "wall"}}, result)
+
+	_, err = cli.AppendTemporalAttributes(context.Background(), nil, "urn:room:1", &model.TemporalEntityFragment{})
+	assert.ErrorIs(t, err, model.ErrEntityFragmentEmpty)
+}
+
+func TestModifyAttributeInstance(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPatch, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/temporal/entities/urn:room:1/attrs/temperature/urn:instance:1", r.URL.Path)
+
+				body, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, `{"@context": ["https://uri.etsi.org/ngsi-ld/v1/ngsi-ld-core-context.jsonld"], "type": "Property", "value": 23}`, string(body))
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.ModifyAttributeInstance(context.Background(), nil, "urn:room:1", "temperature", "urn:instance:1", &model.Property{Value: 23})
+	assert.NoError(t, err)
+
+	otherInstance := "urn:instance:2"
+	err = cli.ModifyAttributeInstance(context.Background(), nil, "urn:room:1", "temperature", "urn:instance:1", &model.Property{Value: 23, InstanceID: &otherInstance})
+	assert.ErrorIs(t, err, client.ErrInvalidAttributeOptions)
+
+	err = cli.ModifyAttributeInstance(context.Background(), nil, "urn:room:1", "temperature", "", &model.Property{Value: 23})
+	assert.ErrorIs(t, err, client.ErrInvalidAttributeOptions)
+
+	err = cli.ModifyAttributeInstance(context.Background(), nil, "urn:room:1", "temperature", "urn:instance:1", nil)
+	assert.ErrorIs(t, err, client.ErrInvalidAttributeOptions)
+}
+
+func TestDeleteAttributeInstance(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodDelete, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/temporal/entities/urn:room:1/attrs/temperature/urn:instance:1", r.URL.Path)
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.DeleteAttributeInstance(context.Background(), nil, "urn:room:1", "temperature", "urn:instance:1")
+	assert.NoError(t, err)
+}
+
+func TestDeleteAttributeInstanceNotFound(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, err := w.Write([]byte(`{
+          "type": "https://uri.etsi.org/ngsi-ld/errors/ResourceNotFound",
+          "title": "Instance not found"
+        }`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.DeleteAttributeInstance(context.Background(), nil, "urn:room:1", "temperature", "urn:instance:1")
+	assert.ErrorIs(t, err, client.ErrNgsiLdResourceNotFound)
+}
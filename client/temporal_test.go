@@ -0,0 +1,285 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsi-gold/client"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/phoops/ngsi-gold/query"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetrieveTemporalEntity(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Assertion on the received request
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/temporal/entities/urn:room:1", r.URL.Path)
+				assert.Equal(t, "temperature", r.URL.Query().Get("attrs"))
+				assert.Equal(t, "after", r.URL.Query().Get("timerel"))
+				assert.Equal(t, "2022-03-01T10:00:00Z", r.URL.Query().Get("timeAt"))
+				assert.Equal(t, "5", r.URL.Query().Get("lastN"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`{
+          "id": "urn:room:1",
+          "type": "Room",
+          "temperature": [
+            {"type": "Property", "value": 21.5, "observedAt": "2022-03-01T10:30:00Z"},
+            {"type": "Property", "value": 22.5, "observedAt": "2022-03-01T11:30:00Z"}
+          ]
+        }`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entity, err := cli.RetrieveTemporalEntity(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		client.TemporalSetQuery(client.QuerySetAttrs("temperature")),
+		client.TemporalSetAfter(time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)),
+		client.TemporalSetLastN(5),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:room:1", entity.ID)
+	assert.Len(t, entity.Properties["temperature"], 2)
+	assert.Equal(t, 22.5, entity.Properties["temperature"][1].Value)
+}
+
+func TestQueryTemporalEntities(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/ngsi-ld/v1/temporal/entities", r.URL.Path)
+				assert.Equal(t, "Room", r.URL.Query().Get("type"))
+				assert.Equal(t, "between", r.URL.Query().Get("timerel"))
+				assert.Equal(t, "2022-03-01T10:00:00Z", r.URL.Query().Get("timeAt"))
+				assert.Equal(t, "2022-03-02T10:00:00Z", r.URL.Query().Get("endTimeAt"))
+				assert.Equal(t, "modifiedAt", r.URL.Query().Get("timeproperty"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`[
+          {"id": "urn:room:1", "type": "Room", "temperature": [{"type": "Property", "value": 21.5}]},
+          {"id": "urn:room:2", "type": "Room", "wall": [{"type": "Relationship", "object": "urn:wall:1"}]}
+        ]`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	timeAt := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	entities, err := cli.QueryTemporalEntities(
+		context.Background(),
+		nil,
+		client.TemporalSetQuery(client.QuerySetTypes("Room")),
+		client.TemporalSetBetween(timeAt, timeAt.Add(24*time.Hour)),
+		client.TemporalSetTimeProperty("modifiedAt"),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, entities, 2)
+	assert.Equal(t, "urn:room:2", entities[1].ID)
+	assert.Equal(t, []model.Relationship{{Object: "urn:wall:1"}}, entities[1].Relationships["wall"])
+}
+
+func TestQueryTemporalEntitiesScope(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/Italy/#", r.URL.Query().Get("scopeQ"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`[
+          {"id": "urn:room:1", "type": "Room", "scope": ["/Italy/Tuscany", "/Company/Sales"], "temperature": [{"type": "Property", "value": 21.5}]}
+        ]`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entities, err := cli.QueryTemporalEntities(
+		context.Background(),
+		nil,
+		client.TemporalSetQuery(client.QuerySetScopeQ(query.Scope("Italy").Descendants())),
+		client.TemporalSetBefore(time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, entities, 1)
+	assert.Equal(t, []string{"/Italy/Tuscany", "/Company/Sales"}, entities[0].Scope)
+}
+
+func TestQueryTemporalEntitiesInvalidOptions(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("http://localhost:1026"),
+	)
+	assert.NoError(t, err)
+
+	timeAt := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	tests := map[string][]client.TemporalOptionFunc{
+		"missing temporal query": {
+			client.TemporalSetQuery(client.QuerySetTypes("Room")),
+		},
+		"missing selector": {
+			client.TemporalSetBefore(timeAt),
+		},
+		"keyValues": {
+			client.TemporalSetQuery(client.QuerySetTypes("Room"), client.QuerySetKeyValues),
+			client.TemporalSetBefore(timeAt),
+		},
+		"invalid interval": {
+			client.TemporalSetQuery(client.QuerySetTypes("Room")),
+			client.TemporalSetBetween(timeAt, timeAt.Add(-time.Hour)),
+		},
+		"time property without query": {
+			client.TemporalSetQuery(client.QuerySetTypes("Room")),
+			client.TemporalSetTimeProperty("observedAt"),
+		},
+		"invalid lastN": {
+			client.TemporalSetQuery(client.QuerySetTypes("Room")),
+			client.TemporalSetBefore(timeAt),
+			client.TemporalSetLastN(0),
+		},
+	}
+
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := cli.QueryTemporalEntities(context.Background(), nil, opts...)
+			assert.ErrorIs(t, err, client.ErrInvalidTemporalOptions)
+		})
+	}
+}
+
+func TestQueryTemporalEntitiesValues(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/ngsi-ld/v1/temporal/entities", r.URL.Path)
+				assert.Equal(t, "temporalValues,sysAttrs", r.URL.Query().Get("options"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`[{
+          "id": "urn:room:1",
+          "type": "Room",
+          "temperature": {"type": "Property", "values": [[21.5, "2022-03-01T10:30:00Z"]]}
+        }]`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entities, err := cli.QueryTemporalEntitiesValues(
+		context.Background(),
+		nil,
+		client.TemporalSetQuery(client.QuerySetTypes("Room"), client.QuerySetSysAttrs),
+		client.TemporalSetAfter(time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, entities, 1)
+	assert.Equal(t, model.TimeSeries{
+		{Time: time.Date(2022, 3, 1, 10, 30, 0, 0, time.UTC), Value: 21.5},
+	}, entities[0].Properties["temperature"].Default())
+}
+
+func TestRetrieveAggregatedTemporalEntity(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/ngsi-ld/v1/temporal/entities/urn:room:1", r.URL.Path)
+				assert.Equal(t, "aggregatedValues", r.URL.Query().Get("options"))
+				assert.Equal(t, "avg,max", r.URL.Query().Get("aggrMethods"))
+				assert.Equal(t, "P1D", r.URL.Query().Get("aggrPeriodDuration"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`{
+          "id": "urn:room:1",
+          "type": "Room",
+          "temperature": {
+            "type": "Property",
+            "avg": [[21.5, "2022-03-01T00:00:00Z", "2022-03-02T00:00:00Z"]],
+            "max": [[25, "2022-03-01T00:00:00Z", "2022-03-02T00:00:00Z"]]
+          }
+        }`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	entity, err := cli.RetrieveAggregatedTemporalEntity(
+		context.Background(),
+		nil,
+		"urn:room:1",
+		client.TemporalSetAggrMethods(model.AggrAvg, model.AggrMax),
+		client.TemporalSetAggrPeriodDuration("P1D"),
+	)
+	assert.NoError(t, err)
+	avg, err := entity.Properties["temperature"].Default().Series(model.AggrAvg).Float64s()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Float64Point{{Time: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), Value: 21.5}}, avg)
+}
+
+func TestAggregatedTemporalInvalidOptions(t *testing.T) {
+	cli, err := client.New(
+		client.SetURL("http://localhost:1026"),
+	)
+	assert.NoError(t, err)
+
+	tests := map[string][]client.TemporalOptionFunc{
+		"missing methods": {
+			client.TemporalSetAggrPeriodDuration("PT1H"),
+		},
+		"unknown method": {
+			client.TemporalSetAggrMethods("median"),
+		},
+		"invalid duration": {
+			client.TemporalSetAggrMethods(model.AggrAvg),
+			client.TemporalSetAggrPeriodDuration("1 day"),
+		},
+		"empty duration": {
+			client.TemporalSetAggrMethods(model.AggrAvg),
+			client.TemporalSetAggrPeriodDuration("PT"),
+		},
+	}
+
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := cli.RetrieveAggregatedTemporalEntity(context.Background(), nil, "urn:room:1", opts...)
+			assert.ErrorIs(t, err, client.ErrInvalidTemporalOptions)
+		})
+	}
+
+	_, err = cli.RetrieveTemporalEntity(context.Background(), nil, "urn:room:1", client.TemporalSetAggrMethods(model.AggrAvg))
+	assert.ErrorIs(t, err, client.ErrInvalidTemporalOptions)
+}
@@ -0,0 +1,151 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phoops/ngsi-gold/client"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertSuccess(t *testing.T) {
+	type testCase struct {
+		name    string
+		opts    []client.UpsertOptionFunc
+		options string
+	}
+
+	tests := []testCase{
+		{
+			name:    "default replace mode",
+			options: "replace",
+		},
+		{
+			name:    "update mode",
+			opts:    []client.UpsertOptionFunc{client.UpsertSetUpdateMode},
+			options: "update",
+		},
+	}
+
+	for _, y := range tests {
+		t.Run(y.name, func(t *testing.T) {
+			ts := httptest.NewServer(
+				http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						assert.Equal(t, http.MethodPost, r.Method)
+						assert.Equal(t, "/ngsi-ld/v1/entityOperations/upsert", r.URL.Path)
+						assert.Equal(t, "application/ld+json", r.Header.Get("Content-Type"))
+						assert.Equal(t, y.options, r.URL.Query().Get("options"))
+
+						w.WriteHeader(http.StatusNoContent)
+					}))
+			defer ts.Close()
+
+			cli, err := client.New(
+				client.SetURL(ts.URL),
+			)
+			assert.NoError(t, err)
+
+			err = cli.BatchUpsertEntities(context.Background(), testBatchPayload(), y.opts...)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUpsertPartialFailure(t *testing.T) {
+	type testCase struct {
+		name string
+		body string
+	}
+
+	tests := []testCase{
+		{
+			name: "Scorpio error items",
+			body: `{
+        "success": ["urn:sensor:1"],
+        "errors": [
+          {
+            "@id": "urn:sensor:2",
+            "ProblemDetails": {
+              "type": "https://uri.etsi.org/ngsi-ld/errors/BadRequestData",
+              "title": "Bad request data",
+              "detail": "invalid attribute"
+            }
+          }
+        ]
+      }`,
+		},
+		{
+			name: "specification error items",
+			body: `{
+        "success": ["urn:sensor:1"],
+        "errors": [
+          {
+            "entityId": "urn:sensor:2",
+            "error": {
+              "type": "https://uri.etsi.org/ngsi-ld/errors/BadRequestData",
+              "title": "Bad request data",
+              "detail": "invalid attribute"
+            }
+          }
+        ]
+      }`,
+		},
+	}
+
+	for _, y := range tests {
+		t.Run(y.name, func(t *testing.T) {
+			ts := httptest.NewServer(
+				http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						w.Header().Set("Content-Type", "application/json")
+						w.WriteHeader(http.StatusMultiStatus)
+						_, err := w.Write([]byte(y.body))
+						assert.NoError(t, err)
+					}))
+			defer ts.Close()
+
+			cli, err := client.New(
+				client.SetURL(ts.URL),
+			)
+			assert.NoError(t, err)
+
+			err = cli.BatchUpsertEntities(context.Background(), testBatchPayload())
+			assert.ErrorIs(t, err, client.ErrNgsiMixedResponse)
+
+			var batchErr *client.BatchError
+			assert.True(t, errors.As(err, &batchErr))
+			assert.Equal(t, []string{"urn:sensor:1"}, batchErr.Result.Success)
+			assert.Equal(t, []string{"urn:sensor:2"}, batchErr.Result.FailedIDs())
+			assert.Equal(t, "invalid attribute", batchErr.Result.Errors[0].ProblemDetails.Detail)
+			assert.ErrorIs(t, batchErr.Result.Errors[0].Err(), client.ErrNgsiBadData)
+		})
+	}
+}
+
+func TestUpsertInvalidRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, err := w.Write([]byte(`{"type": "https://uri.etsi.org/ngsi-ld/errors/InvalidRequest", "title": "Invalid request"}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	cli, err := client.New(
+		client.SetURL(ts.URL),
+	)
+	assert.NoError(t, err)
+
+	err = cli.BatchUpsertEntities(context.Background(), testBatchPayload())
+	assert.ErrorIs(t, err, client.ErrNgsiLdInvalidRequest)
+
+	var batchErr *client.BatchError
+	assert.False(t, errors.As(err, &batchErr))
+}
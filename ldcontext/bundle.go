@@ -0,0 +1,84 @@
+package ldcontext
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+
+	"github.com/pkg/errors"
+)
+
+// The bundled copy of the core context is its version 1.8, the one served
+// by the unversioned URL as well.
+// Smart Data Models contexts are not bundled: applications needing them offline
+// embed their own copies in a BundleLoader, chained before DefaultBundle.
+//
+//go:embed contexts/*.jsonld
+var bundledContexts embed.FS
+
+const (
+	coreContextFile   = "contexts/ngsi-ld-core-context-v1.8.jsonld"
+	coreContextV18URL = "https://uri.etsi.org/ngsi-ld/v1/ngsi-ld-core-context-v1.8.jsonld"
+)
+
+// BundleLoader serves the contexts stored in a file system, e.g. embedded in
+// the binary, so that they are available without network access
+type BundleLoader struct {
+	fsys  fs.FS
+	files map[string]string
+}
+
+// NewBundleLoader returns a loader serving, for each URL, the JSON-LD document
+// stored in the file system at the associated path
+func NewBundleLoader(fsys fs.FS, files map[string]string) *BundleLoader {
+	bundle := map[string]string{}
+	for contextURL, path := range files {
+		bundle[contextURL] = path
+	}
+	return &BundleLoader{fsys: fsys, files: bundle}
+}
+
+// DefaultBundle serves the bundled copy of the core context, and no other context
+var DefaultBundle = NewBundleLoader(bundledContexts, map[string]string{
+	CoreContextURL:    coreContextFile,
+	coreContextV18URL: coreContextFile,
+})
+
+func (l *BundleLoader) LoadContext(ctx context.Context, contextURL string) (LdContext, error) {
+	path, ok := l.files[contextURL]
+	if !ok {
+		return nil, errors.Wrapf(ErrContextNotAvailable, "URL: %s: not bundled", contextURL)
+	}
+	b, err := fs.ReadFile(l.fsys, path)
+	if err != nil {
+		return nil, errors.Wrapf(ErrContextNotAvailable, "URL: %s: %s", contextURL, err)
+	}
+	return decodeDocument(contextURL, b)
+}
+
+// ChainLoader asks each of the loaders in turn, until one of them provides the context,
+// e.g. a BundleLoader before a CachingLoader
+type ChainLoader []DocumentLoader
+
+func (l ChainLoader) LoadContext(ctx context.Context, contextURL string) (LdContext, error) {
+	err := errors.Wrapf(ErrContextNotAvailable, "URL: %s: no loader", contextURL)
+	for _, loader := range l {
+		var ldCtx LdContext
+		ldCtx, err = loader.LoadContext(ctx, contextURL)
+		if err == nil {
+			return ldCtx, nil
+		}
+	}
+	return nil, err
+}
+
+// coreContext is resolved from the bundle, without network access
+var coreContext = mustLoadCore()
+
+func mustLoadCore() LdContext {
+	ldCtx, err := DefaultBundle.LoadContext(context.Background(), CoreContextURL)
+	if err != nil {
+		panic(err)
+	}
+	return ldCtx
+}
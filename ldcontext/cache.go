@@ -0,0 +1,233 @@
+package ldcontext
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultCacheTTL is how long contexts are cached when the server gives no hint
+const defaultCacheTTL = 24 * time.Hour
+
+// CachingLoader fetches the contexts from the network and caches them in memory,
+// and on disk if a directory is set. Entries expire according to the Cache-Control
+// and Expires headers of the responses. Expired entries are still served when the
+// context can't be fetched again, e.g. in air-gapped deployments.
+type CachingLoader struct {
+	remote     HTTPLoader
+	dir        string
+	defaultTTL time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	URL     string    `json:"url"`
+	Expires time.Time `json:"expires"`
+	Context LdContext `json:"context"`
+}
+
+type CacheOptionFunc func(*CachingLoader) error
+
+// CacheSetHTTPClient sets the client fetching the contexts
+func CacheSetHTTPClient(client *http.Client) CacheOptionFunc {
+	return func(l *CachingLoader) error {
+		if client == nil {
+			return errors.New("nil HTTP client")
+		}
+		l.remote.Client = client
+		return nil
+	}
+}
+
+// CacheSetDir keeps the cached contexts in the directory too, so that they survive restarts
+func CacheSetDir(dir string) CacheOptionFunc {
+	return func(l *CachingLoader) error {
+		if dir == "" {
+			return errors.New("empty cache directory")
+		}
+		err := os.MkdirAll(dir, 0o755)
+		if err != nil {
+			return err
+		}
+		l.dir = dir
+		return nil
+	}
+}
+
+// CacheSetDefaultTTL sets how long contexts are cached when the response has no cache headers
+func CacheSetDefaultTTL(ttl time.Duration) CacheOptionFunc {
+	return func(l *CachingLoader) error {
+		if ttl < 0 {
+			return errors.New("negative cache TTL")
+		}
+		l.defaultTTL = ttl
+		return nil
+	}
+}
+
+// CacheSetClock sets the function returning the current time, used to expire entries
+func CacheSetClock(now func() time.Time) CacheOptionFunc {
+	return func(l *CachingLoader) error {
+		if now == nil {
+			return errors.New("nil clock")
+		}
+		l.now = now
+		return nil
+	}
+}
+
+func NewCachingLoader(opts ...CacheOptionFunc) (*CachingLoader, error) {
+	l := &CachingLoader{
+		defaultTTL: defaultCacheTTL,
+		now:        time.Now,
+		entries:    map[string]cacheEntry{},
+	}
+	for _, o := range opts {
+		err := o(l)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidCacheOptions, err.Error())
+		}
+	}
+	return l, nil
+}
+
+func (l *CachingLoader) LoadContext(ctx context.Context, contextURL string) (LdContext, error) {
+	entry, cached := l.lookup(contextURL)
+	if cached && l.now().Before(entry.Expires) {
+		return entry.Context, nil
+	}
+
+	ldCtx, expires, cacheable, err := l.fetch(ctx, contextURL)
+	if err != nil {
+		// Better stale than nothing
+		if cached {
+			return entry.Context, nil
+		}
+		return nil, err
+	}
+
+	if cacheable {
+		l.store(cacheEntry{URL: contextURL, Expires: expires, Context: ldCtx})
+	}
+	return ldCtx, nil
+}
+
+// fetch retrieves the context, returning when it expires and whether it can be stored
+func (l *CachingLoader) fetch(ctx context.Context, contextURL string) (LdContext, time.Time, bool, error) {
+	bodyBytes, header, err := l.remote.fetch(ctx, contextURL)
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+	ldCtx, err := decodeDocument(contextURL, bodyBytes)
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+
+	now := l.now()
+	expires := now.Add(l.defaultTTL)
+
+	cacheControl := parseCacheControl(header.Get("Cache-Control"))
+	if _, ok := cacheControl["no-store"]; ok {
+		return ldCtx, now, false, nil
+	}
+	if _, ok := cacheControl["no-cache"]; ok {
+		return ldCtx, now, true, nil
+	}
+	if maxAge, ok := cacheControl["max-age"]; ok {
+		seconds, err := strconv.Atoi(maxAge)
+		if err == nil && seconds >= 0 {
+			return ldCtx, now.Add(time.Duration(seconds) * time.Second), true, nil
+		}
+	}
+	if header.Get("Expires") != "" {
+		// An invalid date means already expired
+		at, err := http.ParseTime(header.Get("Expires"))
+		if err != nil {
+			at = now
+		}
+		return ldCtx, at, true, nil
+	}
+	return ldCtx, expires, true, nil
+}
+
+func parseCacheControl(value string) map[string]string {
+	directives := map[string]string{}
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(directive, "=")
+		directives[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(arg), `"`)
+	}
+	return directives
+}
+
+func (l *CachingLoader) lookup(contextURL string) (cacheEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[contextURL]
+	if ok || l.dir == "" {
+		return entry, ok
+	}
+
+	b, err := os.ReadFile(l.path(contextURL))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	err = json.Unmarshal(b, &entry)
+	if err != nil || entry.URL != contextURL {
+		return cacheEntry{}, false
+	}
+	l.entries[contextURL] = entry
+	return entry, true
+}
+
+func (l *CachingLoader) store(entry cacheEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[entry.URL] = entry
+	if l.dir == "" {
+		return
+	}
+
+	// The disk cache is best effort, the memory one is enough to serve the context
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(l.dir, "context-*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(b)
+	closeErr := tmp.Close()
+	if err != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if os.Rename(tmp.Name(), l.path(entry.URL)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// path returns the file caching the context on disk
+func (l *CachingLoader) path(contextURL string) string {
+	sum := sha256.Sum256([]byte(contextURL))
+	return filepath.Join(l.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,170 @@
+package ldcontext_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachingLoader(t *testing.T) {
+	requests := 0
+	cacheControl := "max-age=60"
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Header().Set("Content-Type", "application/ld+json")
+				w.Header().Set("Cache-Control", cacheControl)
+				_, err := w.Write([]byte(`{"@context": {"temperature": "https://example.org/temperature"}}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	now := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	loader, err := ldcontext.NewCachingLoader(
+		ldcontext.CacheSetClock(func() time.Time { return now }),
+	)
+	assert.NoError(t, err)
+
+	expected := ldcontext.LdContext{map[string]any{"temperature": "https://example.org/temperature"}}
+	for i := 0; i < 3; i++ {
+		ldCtx, err := loader.LoadContext(context.Background(), ts.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ldCtx)
+	}
+	assert.Equal(t, 1, requests)
+
+	// Expired entries are fetched again
+	now = now.Add(time.Minute)
+	_, err = loader.LoadContext(context.Background(), ts.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests)
+
+	// Contexts that must not be stored are always fetched
+	cacheControl = "no-store"
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		_, err = loader.LoadContext(context.Background(), ts.URL)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 4, requests)
+}
+
+func TestCachingLoaderExpires(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Header().Set("Expires", "Tue, 01 Mar 2022 11:00:00 GMT")
+				_, err := w.Write([]byte(`{"@context": "https://example.org/context.jsonld"}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	now := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	loader, err := ldcontext.NewCachingLoader(
+		ldcontext.CacheSetClock(func() time.Time { return now }),
+	)
+	assert.NoError(t, err)
+
+	_, err = loader.LoadContext(context.Background(), ts.URL)
+	assert.NoError(t, err)
+	now = now.Add(59 * time.Minute)
+	_, err = loader.LoadContext(context.Background(), ts.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	now = now.Add(time.Minute)
+	_, err = loader.LoadContext(context.Background(), ts.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestCachingLoaderDisk(t *testing.T) {
+	available := true
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if !available {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Header().Set("Cache-Control", "max-age=60")
+				_, err := w.Write([]byte(`{"@context": {"temperature": "https://example.org/temperature"}}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	now := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	clock := ldcontext.CacheSetClock(func() time.Time { return now })
+
+	loader, err := ldcontext.NewCachingLoader(ldcontext.CacheSetDir(dir), clock)
+	assert.NoError(t, err)
+	_, err = loader.LoadContext(context.Background(), ts.URL)
+	assert.NoError(t, err)
+
+	// A new loader finds the context on disk, and serves it stale when the server is gone
+	available = false
+	now = now.Add(time.Hour)
+	loader, err = ldcontext.NewCachingLoader(ldcontext.CacheSetDir(dir), clock)
+	assert.NoError(t, err)
+	ldCtx, err := loader.LoadContext(context.Background(), ts.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, ldcontext.LdContext{map[string]any{"temperature": "https://example.org/temperature"}}, ldCtx)
+
+	_, err = loader.LoadContext(context.Background(), ts.URL+"/missing")
+	assert.ErrorIs(t, err, ldcontext.ErrContextNotAvailable)
+}
+
+func TestCachingLoaderInvalidOptions(t *testing.T) {
+	_, err := ldcontext.NewCachingLoader(ldcontext.CacheSetDefaultTTL(-time.Second))
+	assert.ErrorIs(t, err, ldcontext.ErrInvalidCacheOptions)
+}
+
+func TestBundleLoader(t *testing.T) {
+	// The core context is bundled
+	core, err := ldcontext.DefaultBundle.LoadContext(context.Background(), ldcontext.CoreContextURL)
+	assert.NoError(t, err)
+	activeCtx, err := ldcontext.Resolve(context.Background(), core, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://uri.etsi.org/ngsi-ld/observedAt", activeCtx.ExpandTerm("observedAt"))
+	assert.Equal(t, "https://uri.etsi.org/ngsi-ld/hasPreviousValue", activeCtx.ExpandTerm("previousValue"))
+	assert.Equal(t, "https://purl.org/geojson/vocab#bbox", activeCtx.ExpandTerm("bbox"))
+
+	versioned, err := ldcontext.DefaultBundle.LoadContext(context.Background(), "https://uri.etsi.org/ngsi-ld/v1/ngsi-ld-core-context-v1.8.jsonld")
+	assert.NoError(t, err)
+	assert.Equal(t, core, versioned)
+
+	_, err = ldcontext.DefaultBundle.LoadContext(context.Background(), testContextURL)
+	assert.ErrorIs(t, err, ldcontext.ErrContextNotAvailable)
+
+	// Falls back to the following loaders
+	loader := ldcontext.ChainLoader{ldcontext.DefaultBundle, testLoader()}
+	_, err = ldcontext.Resolve(context.Background(), ldcontext.LdContext{testContextURL, ldcontext.CoreContextURL}, loader)
+	assert.NoError(t, err)
+}
+
+func TestBundleLoaderApplicationContexts(t *testing.T) {
+	// An application bundles the domain contexts it uses, next to the core one
+	const domainURL = "https://example.org/smart-data-models/context.jsonld"
+	fsys := fstest.MapFS{
+		"contexts/domain.jsonld": {Data: []byte(`{"@context": {"temperature": "https://example.org/ontology/temperature"}}`)},
+	}
+	loader := ldcontext.ChainLoader{
+		ldcontext.NewBundleLoader(fsys, map[string]string{domainURL: "contexts/domain.jsonld"}),
+		ldcontext.DefaultBundle,
+	}
+
+	activeCtx, err := ldcontext.Resolve(context.Background(), ldcontext.LdContext{domainURL, ldcontext.CoreContextURL}, loader)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.org/ontology/temperature", activeCtx.ExpandTerm("temperature"))
+	assert.Equal(t, "https://uri.etsi.org/ngsi-ld/observedAt", activeCtx.ExpandTerm("observedAt"))
+}
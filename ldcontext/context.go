@@ -1,8 +1,175 @@
 package ldcontext
 
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// CoreContextURL references the ETSI NGSI-LD core @context
+const CoreContextURL = "https://uri.etsi.org/ngsi-ld/v1/ngsi-ld-core-context.jsonld"
+
+// LinkRel is the relation of the Link header referencing a @context
+const LinkRel = "http://www.w3.org/ns/json-ld#context"
+
+// LdContext is a JSON-LD @context: a list of URLs of remote contexts
+// and maps of inline term definitions
 type LdContext []any
 
 var (
-	EmptyContext   LdContext = []any{}
-	DefaultContext           = EmptyContext
+	EmptyContext LdContext = []any{}
+	CoreContext  LdContext = []any{CoreContextURL}
+	// DefaultContext is used whenever no context is provided
+	DefaultContext = CoreContext
 )
+
+// FromURL returns the context referencing the remote contexts
+func FromURL(contextURLs ...string) (LdContext, error) {
+	ldCtx := LdContext{}
+	for _, contextURL := range contextURLs {
+		if !validURL(contextURL) {
+			return nil, errors.Wrapf(ErrContextInvalidURL, "URL: %s", contextURL)
+		}
+		ldCtx = append(ldCtx, contextURL)
+	}
+	return ldCtx, nil
+}
+
+// FromTerms returns the inline context defining the terms.
+// Each term maps to an IRI, or to an expanded term definition
+// (e.g. {"@id": "https://example.org/observedAt", "@type": "DateTime"}).
+func FromTerms(terms map[string]any) (LdContext, error) {
+	inline := map[string]any{}
+	for term, definition := range terms {
+		if term == "" {
+			return nil, ErrContextInvalidTerm
+		}
+		switch definition.(type) {
+		case string, map[string]any, nil:
+		default:
+			return nil, errors.Wrapf(ErrContextInvalidTerm, "term: %s", term)
+		}
+		inline[term] = definition
+	}
+	return LdContext{inline}, nil
+}
+
+// Merge returns a context made of the entries of all the contexts, in order.
+// URLs are listed once, and the core context is moved last as required by
+// NGSI-LD, so that it is never overridden.
+func Merge(contexts ...LdContext) LdContext {
+	merged := LdContext{}
+	seen := map[string]bool{}
+	core := false
+
+	for _, ldCtx := range contexts {
+		for _, entry := range ldCtx {
+			contextURL, ok := entry.(string)
+			if !ok {
+				merged = append(merged, entry)
+				continue
+			}
+			if contextURL == CoreContextURL {
+				core = true
+				continue
+			}
+			if !seen[contextURL] {
+				seen[contextURL] = true
+				merged = append(merged, contextURL)
+			}
+		}
+	}
+
+	if core {
+		merged = append(merged, CoreContextURL)
+	}
+	return merged
+}
+
+// WithCore returns the context with the core context appended
+func (c LdContext) WithCore() LdContext {
+	return Merge(c, CoreContext)
+}
+
+// URLs returns the remote contexts referenced by the context
+func (c LdContext) URLs() []string {
+	urls := []string{}
+	for _, entry := range c {
+		if contextURL, ok := entry.(string); ok {
+			urls = append(urls, contextURL)
+		}
+	}
+	return urls
+}
+
+// LinkHeader returns the value of the Link header referencing the context,
+// for requests carrying no @context in the body.
+// The core context is implied, so a context made only of it needs no header:
+// an empty string is returned. Inline terms or multiple URLs can't be linked.
+func (c LdContext) LinkHeader() (string, error) {
+	linked := ""
+	for _, entry := range c {
+		contextURL, ok := entry.(string)
+		if !ok {
+			return "", ErrContextNotLinkable
+		}
+		if contextURL == CoreContextURL {
+			continue
+		}
+		if linked != "" {
+			return "", ErrContextNotLinkable
+		}
+		linked = contextURL
+	}
+	if linked == "" {
+		return "", nil
+	}
+
+	return fmt.Sprintf(`<%s>; rel="%s"; type="application/ld+json"`, linked, LinkRel), nil
+}
+
+// Validate checks the entries of the context
+func (c LdContext) Validate() error {
+	for _, entry := range c {
+		switch v := entry.(type) {
+		case string:
+			if !validURL(v) {
+				return errors.Wrapf(ErrContextInvalidURL, "URL: %s", v)
+			}
+		case map[string]any:
+			_, err := FromTerms(v)
+			if err != nil {
+				return err
+			}
+		default:
+			return ErrContextInvalidEntry
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts any of the forms of @context: a single URL,
+// a single inline context or a list of them
+func (c *LdContext) UnmarshalJSON(b []byte) error {
+	var raw any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	switch v := raw.(type) {
+	case nil:
+		*c = LdContext{}
+	case []any:
+		*c = LdContext(v)
+	default:
+		*c = LdContext{v}
+	}
+	return c.Validate()
+}
+
+func validURL(contextURL string) bool {
+	u, err := url.Parse(contextURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,125 @@
+package ldcontext_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromURL(t *testing.T) {
+	ldCtx, err := ldcontext.FromURL("https://example.org/context.jsonld")
+	assert.NoError(t, err)
+	assert.Equal(t, ldcontext.LdContext{"https://example.org/context.jsonld"}, ldCtx)
+
+	_, err = ldcontext.FromURL("context.jsonld")
+	assert.ErrorIs(t, err, ldcontext.ErrContextInvalidURL)
+}
+
+func TestFromTerms(t *testing.T) {
+	ldCtx, err := ldcontext.FromTerms(map[string]any{
+		"temperature": "https://example.org/temperature",
+		"lastSeen":    map[string]any{"@id": "https://example.org/lastSeen", "@type": "DateTime"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, ldCtx, 1)
+
+	_, err = ldcontext.FromTerms(map[string]any{"": "https://example.org/empty"})
+	assert.ErrorIs(t, err, ldcontext.ErrContextInvalidTerm)
+
+	_, err = ldcontext.FromTerms(map[string]any{"temperature": 21})
+	assert.ErrorIs(t, err, ldcontext.ErrContextInvalidTerm)
+}
+
+func TestMerge(t *testing.T) {
+	terms := map[string]any{"temperature": "https://example.org/temperature"}
+
+	merged := ldcontext.Merge(
+		ldcontext.CoreContext,
+		ldcontext.LdContext{"https://example.org/a.jsonld", terms},
+		ldcontext.LdContext{"https://example.org/b.jsonld", "https://example.org/a.jsonld"},
+	)
+	assert.Equal(t, ldcontext.LdContext{
+		"https://example.org/a.jsonld",
+		terms,
+		"https://example.org/b.jsonld",
+		ldcontext.CoreContextURL,
+	}, merged)
+
+	withCore := ldcontext.LdContext{"https://example.org/a.jsonld"}.WithCore()
+	assert.Equal(t, ldcontext.LdContext{"https://example.org/a.jsonld", ldcontext.CoreContextURL}, withCore)
+	assert.Equal(t, []string{"https://example.org/a.jsonld", ldcontext.CoreContextURL}, withCore.URLs())
+}
+
+func TestLinkHeader(t *testing.T) {
+	tests := map[string]struct {
+		ldCtx ldcontext.LdContext
+		link  string
+		err   error
+	}{
+		"empty": {
+			ldCtx: ldcontext.EmptyContext,
+		},
+		"core": {
+			ldCtx: ldcontext.CoreContext,
+		},
+		"single URL": {
+			ldCtx: ldcontext.LdContext{"https://example.org/context.jsonld"},
+			link:  `<https://example.org/context.jsonld>; rel="http://www.w3.org/ns/json-ld#context"; type="application/ld+json"`,
+		},
+		"URL with core": {
+			ldCtx: ldcontext.LdContext{"https://example.org/context.jsonld", ldcontext.CoreContextURL},
+			link:  `<https://example.org/context.jsonld>; rel="http://www.w3.org/ns/json-ld#context"; type="application/ld+json"`,
+		},
+		"many URLs": {
+			ldCtx: ldcontext.LdContext{"https://example.org/a.jsonld", "https://example.org/b.jsonld"},
+			err:   ldcontext.ErrContextNotLinkable,
+		},
+		"inline terms": {
+			ldCtx: ldcontext.LdContext{map[string]any{"temperature": "https://example.org/temperature"}},
+			err:   ldcontext.ErrContextNotLinkable,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			link, err := tc.ldCtx.LinkHeader()
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.link, link)
+		})
+	}
+}
+
+func TestUnmarshalLdContext(t *testing.T) {
+	tests := map[string]struct {
+		json  string
+		ldCtx ldcontext.LdContext
+	}{
+		"single URL": {
+			json:  `"https://example.org/context.jsonld"`,
+			ldCtx: ldcontext.LdContext{"https://example.org/context.jsonld"},
+		},
+		"inline": {
+			json:  `{"temperature": "https://example.org/temperature"}`,
+			ldCtx: ldcontext.LdContext{map[string]any{"temperature": "https://example.org/temperature"}},
+		},
+		"list": {
+			json:  `["https://example.org/context.jsonld", "https://uri.etsi.org/ngsi-ld/v1/ngsi-ld-core-context.jsonld"]`,
+			ldCtx: ldcontext.LdContext{"https://example.org/context.jsonld", ldcontext.CoreContextURL},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ldCtx := ldcontext.LdContext{}
+			err := json.Unmarshal([]byte(tc.json), &ldCtx)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.ldCtx, ldCtx)
+		})
+	}
+
+	ldCtx := ldcontext.LdContext{}
+	err := json.Unmarshal([]byte(`[42]`), &ldCtx)
+	assert.ErrorIs(t, err, ldcontext.ErrContextInvalidEntry)
+}
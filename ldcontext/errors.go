@@ -0,0 +1,24 @@
+package ldcontext
+
+import "github.com/pkg/errors"
+
+type ErrInvalidLdContext error
+
+var (
+	ErrContextInvalidURL   ErrInvalidLdContext = errors.New(`@context URLs must be absolute HTTP(S) URLs`)
+	ErrContextInvalidTerm  ErrInvalidLdContext = errors.New(`@context terms must be non empty and map to an IRI or a term definition`)
+	ErrContextInvalidEntry ErrInvalidLdContext = errors.New(`@context entries must be URLs or term maps`)
+	ErrContextNotLinkable  ErrInvalidLdContext = errors.New(`@context must be a single URL to be sent as Link header`)
+	ErrContextRecursive    ErrInvalidLdContext = errors.New(`@context can't include itself, nor define terms recursively`)
+)
+
+type ErrContextResolution error
+
+var (
+	ErrContextNotAvailable ErrContextResolution = errors.New(`@context can't be loaded`)
+	ErrContextNoLoader     ErrContextResolution = errors.New(`@context references remote contexts, but no DocumentLoader is available`)
+)
+
+type ErrInvalidLoaderOptions error
+
+var ErrInvalidCacheOptions ErrInvalidLoaderOptions = errors.New(`Invalid options provided for the caching loader`)
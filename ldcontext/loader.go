@@ -0,0 +1,85 @@
+package ldcontext
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// DocumentLoader retrieves the remote contexts referenced by URL
+type DocumentLoader interface {
+	// LoadContext returns the @context of the document found at the URL
+	LoadContext(ctx context.Context, contextURL string) (LdContext, error)
+}
+
+// MapLoader serves preloaded contexts, identified by their URL
+type MapLoader map[string]LdContext
+
+func (l MapLoader) LoadContext(ctx context.Context, contextURL string) (LdContext, error) {
+	ldCtx, ok := l[contextURL]
+	if !ok {
+		return nil, errors.Wrapf(ErrContextNotAvailable, "URL: %s", contextURL)
+	}
+	return ldCtx, nil
+}
+
+// HTTPLoader fetches the contexts from the network
+type HTTPLoader struct {
+	// Client sends the requests, http.DefaultClient when nil
+	Client *http.Client
+}
+
+func (l *HTTPLoader) LoadContext(ctx context.Context, contextURL string) (LdContext, error) {
+	bodyBytes, _, err := l.fetch(ctx, contextURL)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDocument(contextURL, bodyBytes)
+}
+
+// fetch retrieves the document at the URL, returning the response headers as well
+func (l *HTTPLoader) fetch(ctx context.Context, contextURL string) ([]byte, http.Header, error) {
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, contextURL, nil)
+	if err != nil {
+		return nil, nil, errors.Wrapf(ErrContextNotAvailable, "URL: %s: %s", contextURL, err)
+	}
+	req.Header.Set("Accept", "application/ld+json, application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, errors.Wrapf(ErrContextNotAvailable, "URL: %s: %s", contextURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, errors.Wrapf(ErrContextNotAvailable, "URL: %s: status code %d", contextURL, resp.StatusCode)
+	}
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, errors.Wrapf(ErrContextNotAvailable, "URL: %s: %s", contextURL, err)
+	}
+	return bodyBytes, resp.Header, nil
+}
+
+// decodeDocument extracts the @context of a JSON-LD document
+func decodeDocument(contextURL string, b []byte) (LdContext, error) {
+	document := struct {
+		Context *LdContext `json:"@context"`
+	}{}
+	err := json.Unmarshal(b, &document)
+	if err != nil {
+		return nil, errors.Wrapf(ErrContextNotAvailable, "URL: %s: %s", contextURL, err)
+	}
+	if document.Context == nil {
+		return nil, errors.Wrapf(ErrContextNotAvailable, "URL: %s: missing @context", contextURL)
+	}
+	return *document.Context, nil
+}
@@ -0,0 +1,350 @@
+package ldcontext
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxRemoteContexts bounds the number of remote contexts loaded by a resolution
+const maxRemoteContexts = 64
+
+// TermDefinition is the meaning of a term in an active context
+type TermDefinition struct {
+	ID        string // IRI, or keyword, the term expands to
+	Type      string // Type coercion of the values, e.g. @id or an IRI
+	Container string // Container of the values, e.g. @list
+}
+
+// ActiveContext is a resolved @context, mapping each term to its definition
+type ActiveContext struct {
+	vocab string
+	terms map[string]TermDefinition
+}
+
+// Resolve processes the context into an active context, loading the remote
+// contexts with the loader. The core context is always in effect and is
+// applied last, so that its terms are never overridden: its @vocab applies
+// only when the context doesn't set one. The core context is built in, the
+// loader is never asked for it.
+func Resolve(ctx context.Context, ldCtx LdContext, loader DocumentLoader) (*ActiveContext, error) {
+	r := &resolver{
+		ctx:     ctx,
+		loader:  loader,
+		active:  &ActiveContext{terms: map[string]TermDefinition{}},
+		loading: map[string]bool{},
+	}
+
+	userCtx := LdContext{}
+	for _, entry := range ldCtx {
+		if entry != CoreContextURL {
+			userCtx = append(userCtx, entry)
+		}
+	}
+	err := r.process(userCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	vocab := r.active.vocab
+	err = r.process(coreContext)
+	if err != nil {
+		return nil, err
+	}
+	if vocab != "" {
+		r.active.vocab = vocab
+	}
+	return r.active, nil
+}
+
+// Vocab returns the IRI prepended to the terms without definition
+func (c *ActiveContext) Vocab() string {
+	return c.vocab
+}
+
+// Term returns the definition of the term
+func (c *ActiveContext) Term(term string) (TermDefinition, bool) {
+	def, ok := c.terms[term]
+	return def, ok
+}
+
+// ExpandTerm returns the IRI of the term, e.g. of the name of an Attribute or of a type.
+// Keywords and absolute IRIs are returned unchanged.
+func (c *ActiveContext) ExpandTerm(term string) string {
+	if strings.HasPrefix(term, "@") {
+		return term
+	}
+	if def, ok := c.terms[term]; ok {
+		return def.ID
+	}
+	if prefix, suffix, ok := strings.Cut(term, ":"); ok {
+		if strings.HasPrefix(suffix, "//") {
+			return term
+		}
+		if def, ok := c.terms[prefix]; ok {
+			return def.ID + suffix
+		}
+		return term
+	}
+	if c.vocab != "" {
+		return c.vocab + term
+	}
+	return term
+}
+
+// CompactIRI returns the shortest term expanding to the IRI: a term defined as the IRI,
+// a term relative to @vocab or a compact IRI (prefix:suffix), in this order.
+// The IRI is returned unchanged when it can't be compacted.
+func (c *ActiveContext) CompactIRI(iri string) string {
+	if strings.HasPrefix(iri, "@") {
+		return iri
+	}
+
+	// Terms are sorted to choose among equivalent candidates deterministically
+	terms := make([]string, 0, len(c.terms))
+	for term := range c.terms {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+
+	if term, ok := shortest(terms, func(term string) (string, bool) {
+		return term, c.terms[term].ID == iri
+	}); ok {
+		return term
+	}
+
+	if c.vocab != "" && strings.HasPrefix(iri, c.vocab) {
+		suffix := strings.TrimPrefix(iri, c.vocab)
+		if _, defined := c.terms[suffix]; suffix != "" && !defined && !strings.Contains(suffix, ":") {
+			return suffix
+		}
+	}
+
+	if compact, ok := shortest(terms, func(term string) (string, bool) {
+		prefix := c.terms[term].ID
+		if strings.Contains(term, ":") || !isPrefixIRI(prefix) || !strings.HasPrefix(iri, prefix) || len(iri) == len(prefix) {
+			return "", false
+		}
+		return term + ":" + strings.TrimPrefix(iri, prefix), true
+	}); ok {
+		return compact
+	}
+	return iri
+}
+
+// shortest returns the shortest of the candidates built from the terms
+func shortest(terms []string, candidate func(term string) (string, bool)) (string, bool) {
+	best := ""
+	found := false
+	for _, term := range terms {
+		value, ok := candidate(term)
+		if ok && (!found || len(value) < len(best)) {
+			best = value
+			found = true
+		}
+	}
+	return best, found
+}
+
+// isPrefixIRI tells if the IRI can be the prefix of compact IRIs
+func isPrefixIRI(iri string) bool {
+	return strings.HasSuffix(iri, "/") || strings.HasSuffix(iri, "#") || strings.HasSuffix(iri, ":")
+}
+
+type resolver struct {
+	ctx     context.Context
+	loader  DocumentLoader
+	active  *ActiveContext
+	loading map[string]bool
+	loaded  int
+}
+
+func (r *resolver) process(ldCtx LdContext) error {
+	for _, entry := range ldCtx {
+		switch v := entry.(type) {
+		case nil:
+			// A null context resets the active one
+			r.active = &ActiveContext{terms: map[string]TermDefinition{}}
+		case string:
+			err := r.processRemote(v)
+			if err != nil {
+				return err
+			}
+		case map[string]any:
+			err := r.processInline(v)
+			if err != nil {
+				return err
+			}
+		default:
+			return ErrContextInvalidEntry
+		}
+	}
+	return nil
+}
+
+func (r *resolver) processRemote(contextURL string) error {
+	if contextURL == CoreContextURL {
+		return r.process(coreContext)
+	}
+	if r.loader == nil {
+		return errors.Wrapf(ErrContextNoLoader, "URL: %s", contextURL)
+	}
+	if r.loading[contextURL] {
+		return errors.Wrapf(ErrContextRecursive, "URL: %s", contextURL)
+	}
+	r.loaded++
+	if r.loaded > maxRemoteContexts {
+		return errors.Wrapf(ErrContextNotAvailable, "more than %d remote contexts", maxRemoteContexts)
+	}
+
+	remote, err := r.loader.LoadContext(r.ctx, contextURL)
+	if err != nil {
+		return err
+	}
+	r.loading[contextURL] = true
+	defer delete(r.loading, contextURL)
+	return r.process(remote)
+}
+
+func (r *resolver) processInline(local map[string]any) error {
+	defined := map[string]bool{}
+	if vocab, ok := local["@vocab"]; ok {
+		switch v := vocab.(type) {
+		case nil:
+			r.active.vocab = ""
+		case string:
+			// @vocab may be a term or a compact IRI too
+			expanded, err := r.expand(local, v, defined)
+			if err != nil {
+				return err
+			}
+			r.active.vocab = expanded
+		default:
+			return errors.Wrap(ErrContextInvalidTerm, "@vocab must be an IRI")
+		}
+	}
+
+	for term := range local {
+		if strings.HasPrefix(term, "@") {
+			// Other keywords, e.g. @version or @protected, don't define terms
+			continue
+		}
+		err := r.defineTerm(local, term, defined)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defineTerm adds the term of the local context to the active one. The terms of the
+// local context it depends on, e.g. the prefix of a compact IRI, are defined first.
+func (r *resolver) defineTerm(local map[string]any, term string, defined map[string]bool) error {
+	if done, ok := defined[term]; ok {
+		if !done {
+			return errors.Wrapf(ErrContextRecursive, "term: %s", term)
+		}
+		return nil
+	}
+	defined[term] = false
+
+	def := TermDefinition{}
+	id := ""
+	switch v := local[term].(type) {
+	case nil:
+		delete(r.active.terms, term)
+		defined[term] = true
+		return nil
+	case string:
+		id = v
+	case map[string]any:
+		if rawID, ok := v["@id"]; ok {
+			s, ok := rawID.(string)
+			if !ok {
+				return errors.Wrapf(ErrContextInvalidTerm, "term: %s", term)
+			}
+			id = s
+		}
+		if rawType, ok := v["@type"]; ok {
+			s, ok := rawType.(string)
+			if !ok {
+				return errors.Wrapf(ErrContextInvalidTerm, "term: %s", term)
+			}
+			expanded, err := r.expand(local, s, defined)
+			if err != nil {
+				return err
+			}
+			def.Type = expanded
+		}
+		if rawContainer, ok := v["@container"]; ok {
+			s, ok := rawContainer.(string)
+			if !ok {
+				return errors.Wrapf(ErrContextInvalidTerm, "term: %s", term)
+			}
+			def.Container = s
+		}
+	default:
+		return errors.Wrapf(ErrContextInvalidTerm, "term: %s", term)
+	}
+
+	// A term without @id is an IRI relative to @vocab, or a compact IRI itself
+	if id == "" {
+		id = term
+	}
+	expanded, err := r.expand(local, id, defined)
+	if err != nil {
+		return err
+	}
+	def.ID = expanded
+
+	r.active.terms[term] = def
+	defined[term] = true
+	return nil
+}
+
+// expand returns the IRI of the value, defining first the terms of the
+// local context it refers to
+func (r *resolver) expand(local map[string]any, value string, defined map[string]bool) (string, error) {
+	if strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+
+	dependency := value
+	if prefix, suffix, ok := strings.Cut(value, ":"); ok {
+		if strings.HasPrefix(suffix, "//") {
+			return value, nil
+		}
+		dependency = prefix
+	}
+	if _, ok := local[dependency]; ok {
+		if done, ok := defined[dependency]; !ok || done {
+			err := r.defineTerm(local, dependency, defined)
+			if err != nil {
+				return "", err
+			}
+		} else if dependency == value {
+			// Only a term referring to itself is recursive,
+			// e.g. "temperature": "temperature" is relative to @vocab
+			if _, ok := r.active.terms[dependency]; !ok {
+				return r.vocabIRI(value), nil
+			}
+		}
+	}
+
+	if def, ok := r.active.terms[value]; ok {
+		return def.ID, nil
+	}
+	if dependency != value {
+		return r.active.ExpandTerm(value), nil
+	}
+	return r.vocabIRI(value), nil
+}
+
+func (r *resolver) vocabIRI(value string) string {
+	if r.active.vocab == "" {
+		return value
+	}
+	return r.active.vocab + value
+}
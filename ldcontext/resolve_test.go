@@ -0,0 +1,150 @@
+package ldcontext_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/stretchr/testify/assert"
+)
+
+const testContextURL = "https://example.org/context.jsonld"
+
+func testLoader() ldcontext.MapLoader {
+	return ldcontext.MapLoader{
+		testContextURL: ldcontext.LdContext{map[string]any{
+			"schema":      "https://schema.org/",
+			"Room":        "https://example.org/Room",
+			"temperature": "https://example.org/temperature",
+			"name":        "schema:name",
+			"lastSeen":    map[string]any{"@id": "https://example.org/lastSeen", "@type": "schema:DateTime"},
+		}},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	activeCtx, err := ldcontext.Resolve(context.Background(), ldcontext.LdContext{testContextURL}.WithCore(), testLoader())
+	assert.NoError(t, err)
+
+	tests := map[string]string{
+		"temperature":              "https://example.org/temperature",
+		"name":                     "https://schema.org/name",
+		"schema:description":       "https://schema.org/description",
+		"Room":                     "https://example.org/Room",
+		"humidity":                 "https://uri.etsi.org/ngsi-ld/default-context/humidity",
+		"observedAt":               "https://uri.etsi.org/ngsi-ld/observedAt",
+		"Property":                 "https://uri.etsi.org/ngsi-ld/Property",
+		"https://example.org/wind": "https://example.org/wind",
+		"type":                     "@type",
+	}
+	for term, iri := range tests {
+		assert.Equal(t, iri, activeCtx.ExpandTerm(term), term)
+	}
+
+	def, ok := activeCtx.Term("lastSeen")
+	assert.True(t, ok)
+	assert.Equal(t, ldcontext.TermDefinition{
+		ID:   "https://example.org/lastSeen",
+		Type: "https://schema.org/DateTime",
+	}, def)
+	assert.Equal(t, "https://uri.etsi.org/ngsi-ld/default-context/", activeCtx.Vocab())
+}
+
+func TestCompactIRI(t *testing.T) {
+	activeCtx, err := ldcontext.Resolve(context.Background(), ldcontext.LdContext{testContextURL}, testLoader())
+	assert.NoError(t, err)
+
+	tests := map[string]string{
+		"https://example.org/temperature":                       "temperature",
+		"https://schema.org/name":                               "name",
+		"https://schema.org/description":                        "schema:description",
+		"https://uri.etsi.org/ngsi-ld/default-context/humidity": "humidity",
+		"https://uri.etsi.org/ngsi-ld/hasValue":                 "value",
+		"https://uri.etsi.org/ngsi-ld/unknown":                  "ngsi-ld:unknown",
+		"https://other.org/wind":                                "https://other.org/wind",
+	}
+	for iri, term := range tests {
+		assert.Equal(t, term, activeCtx.CompactIRI(iri), iri)
+		assert.Equal(t, iri, activeCtx.ExpandTerm(activeCtx.CompactIRI(iri)), iri)
+	}
+}
+
+func TestResolveVocab(t *testing.T) {
+	activeCtx, err := ldcontext.Resolve(context.Background(), ldcontext.LdContext{
+		map[string]any{
+			"ex":     "https://example.org/",
+			"@vocab": "ex:vocab/",
+		},
+	}, nil)
+	assert.NoError(t, err)
+
+	// The core context doesn't override the @vocab of the user
+	assert.Equal(t, "https://example.org/vocab/temperature", activeCtx.ExpandTerm("temperature"))
+	assert.Equal(t, "temperature", activeCtx.CompactIRI("https://example.org/vocab/temperature"))
+	// Core terms can't be overridden
+	assert.Equal(t, "https://uri.etsi.org/ngsi-ld/location", activeCtx.ExpandTerm("location"))
+}
+
+func TestResolveErrors(t *testing.T) {
+	loader := ldcontext.MapLoader{
+		"https://example.org/a.jsonld": ldcontext.LdContext{"https://example.org/b.jsonld"},
+		"https://example.org/b.jsonld": ldcontext.LdContext{"https://example.org/a.jsonld"},
+	}
+
+	tests := map[string]struct {
+		ldCtx  ldcontext.LdContext
+		loader ldcontext.DocumentLoader
+		err    error
+	}{
+		"no loader": {
+			ldCtx: ldcontext.LdContext{testContextURL},
+			err:   ldcontext.ErrContextNoLoader,
+		},
+		"unknown context": {
+			ldCtx:  ldcontext.LdContext{"https://example.org/missing.jsonld"},
+			loader: loader,
+			err:    ldcontext.ErrContextNotAvailable,
+		},
+		"recursive context": {
+			ldCtx:  ldcontext.LdContext{"https://example.org/a.jsonld"},
+			loader: loader,
+			err:    ldcontext.ErrContextRecursive,
+		},
+		"invalid term": {
+			ldCtx: ldcontext.LdContext{map[string]any{"temperature": 21}},
+			err:   ldcontext.ErrContextInvalidTerm,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ldcontext.Resolve(context.Background(), tc.ldCtx, tc.loader)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestHTTPLoader(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/context.jsonld" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "application/ld+json")
+				_, err := w.Write([]byte(`{"@context": {"temperature": "https://example.org/temperature"}}`))
+				assert.NoError(t, err)
+			}))
+	defer ts.Close()
+
+	loader := &ldcontext.HTTPLoader{}
+	ldCtx, err := loader.LoadContext(context.Background(), ts.URL+"/context.jsonld")
+	assert.NoError(t, err)
+	assert.Equal(t, ldcontext.LdContext{map[string]any{"temperature": "https://example.org/temperature"}}, ldCtx)
+
+	_, err = loader.LoadContext(context.Background(), ts.URL+"/missing.jsonld")
+	assert.ErrorIs(t, err, ldcontext.ErrContextNotAvailable)
+}
@@ -0,0 +1,213 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// attributeMembers are the members shared by every kind of Attribute
+type attributeMembers struct {
+	observedAt    *time.Time
+	datasetID     *string
+	instanceID    *string
+	createdAt     *time.Time
+	modifiedAt    *time.Time
+	deletedAt     *time.Time
+	subAttributes attributeSet
+}
+
+// marshal adds the members to the JSON object of an Attribute
+func (m attributeMembers) marshal(data map[string]any) {
+	if m.observedAt != nil {
+		data["observedAt"] = m.observedAt.UTC().Format(TimeRFC3339Micro)
+	}
+	if m.datasetID != nil {
+		data["datasetId"] = m.datasetID
+	}
+	if m.instanceID != nil {
+		data["instanceId"] = m.instanceID
+	}
+	marshalSystemAttributes(data, m.createdAt, m.modifiedAt, m.deletedAt)
+	m.subAttributes.marshal(data)
+}
+
+// validate checks the sub-Attributes
+func (m attributeMembers) validate(strictness bool) ValidationResult {
+	return m.subAttributes.validate(strictness)
+}
+
+// hasSystemAttributes tells if the Attribute or its sub-Attributes hold timestamps set by the Context Broker
+func (m attributeMembers) hasSystemAttributes() bool {
+	return systemAttributesSet(m.createdAt, m.modifiedAt, m.deletedAt) || m.subAttributes.hasSystemAttributes()
+}
+
+// attributeSet points to the Attributes of an Entity, or to the sub-Attributes of an Attribute,
+// one set for each kind of Attribute
+type attributeSet struct {
+	properties         *Properties
+	relationships      *Relationships
+	geoProperties      *GeoProperties
+	languageProperties *LanguageProperties
+	vocabProperties    *VocabProperties
+	jsonProperties     *JSONProperties
+	listProperties     *ListProperties
+	listRelationships  *ListRelationships
+}
+
+// decode decodes the members of a JSON object into the set of their kind of Attribute.
+// Members that are not Attributes are skipped, sets without Attributes are left nil.
+func (s attributeSet) decode(jsonValues map[string]json.RawMessage) error {
+	for k, v := range jsonValues {
+		typeName, err := attributeType(v)
+		if err != nil {
+			return errors.Wrapf(err, "cannot unmarshal attribute %s", k)
+		}
+
+		switch typeName {
+		case "Property":
+			err = decodeAttributeInstances(s.properties, k, v, "property")
+		case "Relationship":
+			err = decodeAttributeInstances(s.relationships, k, v, "relationship")
+		case "GeoProperty":
+			err = decodeAttributeInstances(s.geoProperties, k, v, "geoproperty")
+		case "LanguageProperty":
+			err = decodeAttributeInstances(s.languageProperties, k, v, "language property")
+		case "VocabProperty":
+			err = decodeAttributeInstances(s.vocabProperties, k, v, "vocab property")
+		case "JsonProperty":
+			err = decodeAttributeInstances(s.jsonProperties, k, v, "JSON property")
+		case "ListProperty":
+			err = decodeAttributeInstances(s.listProperties, k, v, "list property")
+		case "ListRelationship":
+			err = decodeAttributeInstances(s.listRelationships, k, v, "list relationship")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeAttributeInstances decodes the instances of the Attribute named name into the set
+func decodeAttributeInstances[M ~map[string]S, S any](attributes *M, name string, b []byte, kind string) error {
+	var instances S
+	err := json.Unmarshal(b, &instances)
+	if err != nil {
+		return errors.Wrapf(err, "cannot unmarshal %s %s", kind, name)
+	}
+	if *attributes == nil {
+		*attributes = M{}
+	}
+	(*attributes)[name] = instances
+	return nil
+}
+
+// marshal adds every Attribute of the sets to the JSON object
+func (s attributeSet) marshal(data map[string]any) {
+	marshalAttributes(data, *s.properties)
+	marshalAttributes(data, *s.relationships)
+	marshalAttributes(data, *s.geoProperties)
+	marshalAttributes(data, *s.languageProperties)
+	marshalAttributes(data, *s.vocabProperties)
+	marshalAttributes(data, *s.jsonProperties)
+	marshalAttributes(data, *s.listProperties)
+	marshalAttributes(data, *s.listRelationships)
+}
+
+// validate checks every Attribute of the sets
+func (s attributeSet) validate(strictness bool) ValidationResult {
+	for _, err := range []ValidationResult{
+		validateAttributes(*s.properties, strictness),
+		validateAttributes(*s.relationships, strictness),
+		validateAttributes(*s.geoProperties, strictness),
+		validateAttributes(*s.languageProperties, strictness),
+		validateAttributes(*s.vocabProperties, strictness),
+		validateAttributes(*s.jsonProperties, strictness),
+		validateAttributes(*s.listProperties, strictness),
+		validateAttributes(*s.listRelationships, strictness),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshalSystemAttributes adds the timestamps set by the Context Broker to the JSON object
+func marshalSystemAttributes(data map[string]any, createdAt, modifiedAt, deletedAt *time.Time) {
+	if createdAt != nil {
+		data["createdAt"] = createdAt.UTC().Format(TimeRFC3339Micro)
+	}
+	if modifiedAt != nil {
+		data["modifiedAt"] = modifiedAt.UTC().Format(TimeRFC3339Micro)
+	}
+	if deletedAt != nil {
+		data["deletedAt"] = deletedAt.UTC().Format(TimeRFC3339Micro)
+	}
+}
+
+// systemAttributesSet tells if any of the timestamps set by the Context Broker is present
+func systemAttributesSet(createdAt, modifiedAt, deletedAt *time.Time) bool {
+	return createdAt != nil || modifiedAt != nil || deletedAt != nil
+}
+
+// attributePointer is implemented by the pointers to every kind of Attribute
+type attributePointer[T any] interface {
+	*T
+	members() attributeMembers
+}
+
+// anySystemAttributes tells if any Attribute of the set holds timestamps set by the Context Broker
+func anySystemAttributes[M ~map[string]S, S ~[]T, T any, PT attributePointer[T]](attributes M) bool {
+	for _, instances := range attributes {
+		for i := range instances {
+			if PT(&instances[i]).members().hasSystemAttributes() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasSystemAttributes tells if any Attribute of the sets holds timestamps set by the Context Broker
+func (s attributeSet) hasSystemAttributes() bool {
+	return anySystemAttributes(*s.properties) ||
+		anySystemAttributes(*s.relationships) ||
+		anySystemAttributes(*s.geoProperties) ||
+		anySystemAttributes(*s.languageProperties) ||
+		anySystemAttributes(*s.vocabProperties) ||
+		anySystemAttributes(*s.jsonProperties) ||
+		anySystemAttributes(*s.listProperties) ||
+		anySystemAttributes(*s.listRelationships)
+}
+
+// unmarshalSubAttributes checks the type of the Attribute in b and decodes its sub-Attributes,
+// that is the members of its JSON object but the ones of the fields and the other listed ones
+func unmarshalSubAttributes(b []byte, typeName string, errWrongType error, fields reflect.Type, subAttributes attributeSet, members ...string) error {
+	var jsonValues map[string]json.RawMessage
+	_ = json.Unmarshal(b, &jsonValues)
+
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		jsonTag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if jsonTag != "" && jsonTag != "-" {
+			delete(jsonValues, jsonTag)
+		}
+	}
+	for _, member := range members {
+		delete(jsonValues, member)
+	}
+
+	// Check type or bail out
+	rtype, ok := jsonValues["type"]
+	if !ok || string(rtype) != `"`+typeName+`"` {
+		return errWrongType
+	}
+	delete(jsonValues, "type")
+
+	return subAttributes.decode(jsonValues)
+}
@@ -0,0 +1,198 @@
+package model_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const attributeTypesEntity = `{
+	"id": "urn:ngsi-ld:Building:1",
+	"type": "Building",
+	"name": {
+		"type": "LanguageProperty",
+		"languageMap": {"en": "Town hall", "it": "Municipio"}
+	},
+	"category": {"type": "VocabProperty", "vocab": "commercial"},
+	"usage": {"type": "VocabProperty", "vocab": ["office", "shop"]},
+	"config": {
+		"type": "JsonProperty",
+		"json": {"@id": "not-an-iri", "levels": [1, 2]},
+		"source": {"type": "Property", "value": "manual"}
+	},
+	"readings": {"type": "ListProperty", "valueList": [21.5, 22, 22.5], "unitCode": "CEL"},
+	"route": {
+		"type": "ListRelationship",
+		"objectList": [{"object": "urn:ngsi-ld:Stop:1"}, {"object": "urn:ngsi-ld:Stop:2"}]
+	}
+}`
+
+func TestAttributeTypesRoundTrip(t *testing.T) {
+	e := model.Entity{}
+	err := json.Unmarshal([]byte(attributeTypesEntity), &e)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Validate(true))
+	assert.Nil(t, e.Properties)
+	assert.Nil(t, e.Relationships)
+
+	assert.Equal(t, map[string]string{"en": "Town hall", "it": "Municipio"}, e.LanguageProperties["name"][0].LanguageMap)
+	assert.Equal(t, []string{"commercial"}, e.VocabProperties["category"][0].Vocab)
+	assert.Equal(t, []string{"office", "shop"}, e.VocabProperties["usage"][0].Vocab)
+	assert.Equal(t, map[string]any{"@id": "not-an-iri", "levels": []any{1.0, 2.0}}, e.JSONProperties["config"][0].JSON)
+	assert.Equal(t, "manual", e.JSONProperties["config"][0].Properties["source"][0].Value)
+	assert.Equal(t, []any{21.5, 22.0, 22.5}, e.ListProperties["readings"][0].ValueList)
+	assert.Equal(t, "CEL", *e.ListProperties["readings"][0].UnitCode)
+	assert.Equal(t, []string{"urn:ngsi-ld:Stop:1", "urn:ngsi-ld:Stop:2"}, e.ListRelationships["route"][0].ObjectList)
+
+	b, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.JSONEq(t, attributeTypesEntity, string(b))
+}
+
+func TestAttributeTypesMultiInstance(t *testing.T) {
+	e := model.Entity{}
+	err := json.Unmarshal([]byte(`{
+		"id": "urn:ngsi-ld:Building:1",
+		"type": "Building",
+		"name": [
+			{"type": "LanguageProperty", "languageMap": {"en": "Town hall"}},
+			{"type": "LanguageProperty", "languageMap": {"en": "City hall"}, "datasetId": "urn:ngsi-ld:Dataset:alt"}
+		]
+	}`), &e)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Validate(true))
+
+	p, ok := e.LanguageProperties["name"].ByDataset("urn:ngsi-ld:Dataset:alt")
+	assert.True(t, ok)
+	assert.Equal(t, "City hall", p.LanguageMap["en"])
+}
+
+func TestListRelationshipBareObjects(t *testing.T) {
+	r := model.ListRelationship{}
+	err := json.Unmarshal([]byte(`{"type": "ListRelationship", "objectList": ["urn:a", "urn:b"]}`), &r)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"urn:a", "urn:b"}, r.ObjectList)
+}
+
+func TestAttributeTypesValidate(t *testing.T) {
+	tests := map[string]struct {
+		attribute model.Validatable
+		err       error
+	}{
+		"language property without map": {
+			attribute: &model.LanguageProperty{},
+			err:       model.ErrLanguagePropertyMissingMap,
+		},
+		"vocab property without terms": {
+			attribute: &model.VocabProperty{},
+			err:       model.ErrVocabPropertyMissingVocab,
+		},
+		"vocab property with an empty term": {
+			attribute: &model.VocabProperty{Vocab: []string{""}},
+			err:       model.ErrVocabPropertyInvalidVocab,
+		},
+		"JSON property without value": {
+			attribute: &model.JSONProperty{},
+			err:       model.ErrJSONPropertyMissingJSON,
+		},
+		"list property without values": {
+			attribute: &model.ListProperty{},
+			err:       model.ErrListPropertyMissingValueList,
+		},
+		"list relationship without objects": {
+			attribute: &model.ListRelationship{},
+			err:       model.ErrListRelationshipMissingObjectList,
+		},
+		"list relationship with an empty object": {
+			attribute: &model.ListRelationship{ObjectList: []string{"urn:a", ""}},
+			err:       model.ErrListRelationshipInvalidObjectList,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.attribute.Validate(true), tt.err)
+		})
+	}
+}
+
+func TestAttributeTypesWrongType(t *testing.T) {
+	p := model.LanguageProperty{}
+	err := json.Unmarshal([]byte(`{"type": "Property", "languageMap": {"en": "x"}}`), &p)
+	assert.ErrorIs(t, err, model.ErrLanguagePropertyWrongType)
+
+	l := model.ListProperty{}
+	err = json.Unmarshal([]byte(`{"type": "ListProperty", "valueList": 1}`), &l)
+	assert.Error(t, err)
+}
+
+func TestExpandAttributeTypes(t *testing.T) {
+	e := model.Entity{}
+	err := json.Unmarshal([]byte(attributeTypesEntity), &e)
+	assert.NoError(t, err)
+
+	activeCtx, err := ldcontext.Resolve(context.Background(), ldcontext.LdContext{map[string]any{"@vocab": "https://example.org/"}}, nil)
+	assert.NoError(t, err)
+	expanded, err := e.Expand(activeCtx)
+	assert.NoError(t, err)
+	assert.Contains(t, expanded.LanguageProperties, "https://example.org/name")
+	assert.Contains(t, expanded.ListRelationships, "https://example.org/route")
+	assert.Contains(t, expanded.JSONProperties["https://example.org/config"][0].Properties, "https://example.org/source")
+}
+
+func TestSubAttributeTypes(t *testing.T) {
+	const building = `{
+		"id": "urn:ngsi-ld:Building:1",
+		"type": "Building",
+		"height": {
+			"type": "Property",
+			"value": 30,
+			"label": {"type": "LanguageProperty", "languageMap": {"en": "Height"}},
+			"method": {"type": "VocabProperty", "vocab": "survey"},
+			"raw": {"type": "JsonProperty", "json": {"mm": 30000}},
+			"samples": {"type": "ListProperty", "valueList": [29.9, 30.1]},
+			"surveyors": {"type": "ListRelationship", "objectList": [{"object": "urn:ngsi-ld:Person:1"}]}
+		},
+		"owner": {
+			"type": "Relationship",
+			"object": "urn:ngsi-ld:Company:1",
+			"role": {"type": "LanguageProperty", "languageMap": {"en": "Owner"}}
+		},
+		"name": {
+			"type": "LanguageProperty",
+			"languageMap": {"en": "Town hall"},
+			"aliases": {"type": "ListProperty", "valueList": ["City hall"]}
+		}
+	}`
+
+	e := model.Entity{}
+	err := json.Unmarshal([]byte(building), &e)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Validate(true))
+
+	height := e.Properties["height"][0]
+	assert.Equal(t, "Height", height.LanguageProperties["label"][0].LanguageMap["en"])
+	assert.Equal(t, []string{"survey"}, height.VocabProperties["method"][0].Vocab)
+	assert.Equal(t, map[string]any{"mm": 30000.0}, height.JSONProperties["raw"][0].JSON)
+	assert.Equal(t, []any{29.9, 30.1}, height.ListProperties["samples"][0].ValueList)
+	assert.Equal(t, []string{"urn:ngsi-ld:Person:1"}, height.ListRelationships["surveyors"][0].ObjectList)
+	assert.Equal(t, "Owner", e.Relationships["owner"][0].LanguageProperties["role"][0].LanguageMap["en"])
+	assert.Equal(t, []any{"City hall"}, e.LanguageProperties["name"][0].ListProperties["aliases"][0].ValueList)
+
+	b, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.JSONEq(t, building, string(b))
+
+	activeCtx, err := ldcontext.Resolve(context.Background(), ldcontext.LdContext{map[string]any{"@vocab": "https://example.org/"}}, nil)
+	assert.NoError(t, err)
+	expanded, err := e.Expand(activeCtx)
+	assert.NoError(t, err)
+	assert.Contains(t, expanded.Properties["https://example.org/height"][0].ListRelationships, "https://example.org/surveyors")
+
+	invalid := model.Property{Value: 1, VocabProperties: model.VocabProperties{"method": {{}}}}
+	assert.ErrorIs(t, invalid.Validate(true), model.ErrVocabPropertyMissingVocab)
+}
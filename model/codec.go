@@ -0,0 +1,708 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/philiphil/geojson"
+	"github.com/pkg/errors"
+)
+
+// Kinds of the fields of a struct mapped to an Entity, the second item of their ngsi tag.
+//
+// The fields of the Entity struct are tagged as:
+//
+//	ID          string           `ngsi:",id"`
+//	Type        string           `ngsi:"Room,type"`                          // Room when the field is empty
+//	Temperature float64          `ngsi:"temperature,property,unitCode=CEL"`
+//	Room        string           `ngsi:"refRoom,relationship"`               // object of the Relationship
+//	Location    *geojson.Geometry `ngsi:"location,geoproperty"`
+//
+// An Attribute is mapped to a struct too when it needs metadata or sub-attributes.
+// The struct tags the value of the Attribute, e.g.:
+//
+//	type Temperature struct {
+//		Value      float64    `ngsi:",value"`
+//		ObservedAt time.Time  `ngsi:",observedAt"`
+//		DatasetID  string     `ngsi:",datasetId"`
+//		Accuracy   float64    `ngsi:"accuracy,property"`
+//	}
+//
+// The instances of a multi-attribute, one for each datasetId, are mapped to a slice of such structs.
+//
+// Fields without ngsi tag are ignored. Nil pointers, maps, slices and interfaces
+// are not mapped, neither are zero values with the omitempty option.
+const (
+	CodecID           = "id"
+	CodecType         = "type"
+	CodecProperty     = "property"
+	CodecRelationship = "relationship"
+	CodecGeoProperty  = "geoproperty"
+	CodecValue        = "value"  // Value of a Property or of a GeoProperty
+	CodecObject       = "object" // Object of a Relationship
+	CodecObservedAt   = "observedAt"
+	CodecDatasetID    = "datasetId"
+	CodecUnitCode     = "unitCode"
+)
+
+const codecTag = "ngsi"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// codecField is a field of a struct annotated with an ngsi tag
+type codecField struct {
+	index     int
+	name      string
+	kind      string
+	unitCode  *string
+	omitEmpty bool
+}
+
+// attributeParts are the members of an Attribute of any type
+type attributeParts struct {
+	value         any
+	object        string
+	observedAt    *time.Time
+	datasetID     *string
+	unitCode      *string
+	properties    Properties
+	relationships Relationships
+	geoProperties GeoProperties
+}
+
+// MarshalEntity maps the annotated struct, or pointer to struct, to an Entity.
+// The Entity is not validated.
+func MarshalEntity(v any) (*Entity, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.Wrapf(ErrCodecInvalidTarget, "%T", v)
+	}
+	fields, err := codecFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Entity{
+		Properties:    Properties{},
+		Relationships: Relationships{},
+		GeoProperties: GeoProperties{},
+	}
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		switch f.kind {
+		case CodecID:
+			id, err := encodeString(fv, f)
+			if err != nil {
+				return nil, err
+			}
+			e.ID = id
+		case CodecType:
+			typ, err := encodeString(fv, f)
+			if err != nil {
+				return nil, err
+			}
+			if typ == "" {
+				typ = f.name
+			}
+			e.Type = typ
+		case CodecProperty, CodecRelationship, CodecGeoProperty:
+			instances, err := encodeInstances(fv, f)
+			if err != nil {
+				return nil, err
+			}
+			if len(instances) == 0 {
+				continue
+			}
+			err = e.setAttribute(f, instances)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errors.Wrapf(ErrCodecInvalidTag, "field %s: %s is not allowed in an Entity", rv.Type().Field(f.index).Name, f.kind)
+		}
+	}
+
+	// Do not return empty maps
+	if len(e.Properties) == 0 {
+		e.Properties = nil
+	}
+	if len(e.Relationships) == 0 {
+		e.Relationships = nil
+	}
+	if len(e.GeoProperties) == 0 {
+		e.GeoProperties = nil
+	}
+	return e, nil
+}
+
+func (e *Entity) setAttribute(f codecField, instances []attributeParts) error {
+	if _, ok := e.Properties[f.name]; ok {
+		return errors.Wrapf(ErrCodecInvalidTag, "attribute %s mapped twice", f.name)
+	}
+	if _, ok := e.Relationships[f.name]; ok {
+		return errors.Wrapf(ErrCodecInvalidTag, "attribute %s mapped twice", f.name)
+	}
+	if _, ok := e.GeoProperties[f.name]; ok {
+		return errors.Wrapf(ErrCodecInvalidTag, "attribute %s mapped twice", f.name)
+	}
+
+	switch f.kind {
+	case CodecProperty:
+		for _, parts := range instances {
+			e.Properties[f.name] = append(e.Properties[f.name], parts.property())
+		}
+	case CodecRelationship:
+		for _, parts := range instances {
+			e.Relationships[f.name] = append(e.Relationships[f.name], parts.relationship())
+		}
+	case CodecGeoProperty:
+		// The well-known GeoProperties with several instances belong to the GeoProperties map
+		target := e.geoPropertyField(f.name)
+		if target == nil || len(instances) > 1 {
+			if target != nil && *target != nil {
+				return errors.Wrapf(ErrCodecInvalidTag, "attribute %s mapped twice", f.name)
+			}
+			for _, parts := range instances {
+				g, err := parts.geoProperty(f.name)
+				if err != nil {
+					return err
+				}
+				e.GeoProperties[f.name] = append(e.GeoProperties[f.name], *g)
+			}
+			return nil
+		}
+
+		g, err := instances[0].geoProperty(f.name)
+		if err != nil {
+			return err
+		}
+		if *target != nil {
+			return errors.Wrapf(ErrCodecInvalidTag, "attribute %s mapped twice", f.name)
+		}
+		*target = g
+	}
+	return nil
+}
+
+// geoPropertyField returns the field of the Entity holding the well-known GeoProperty,
+// nil when the GeoProperty belongs to the GeoProperties map
+func (e *Entity) geoPropertyField(name string) **GeoProperty {
+	switch name {
+	case "location":
+		return &e.Location
+	case "observationSpace":
+		return &e.ObservationSpace
+	case "operationSpace":
+		return &e.OperationSpace
+	}
+	return nil
+}
+
+// UnmarshalEntity fills the annotated struct pointed by v with the Entity.
+// The fields of the Attributes missing in the Entity are left untouched.
+func UnmarshalEntity(e *Entity, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Wrapf(ErrCodecInvalidTarget, "%T", v)
+	}
+	if e == nil {
+		return errors.Wrap(ErrCodecInvalidTarget, "nil Entity")
+	}
+	rv = rv.Elem()
+	fields, err := codecFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		var err error
+		switch f.kind {
+		case CodecID:
+			err = decodeValue(fv, f, e.ID)
+		case CodecType:
+			err = decodeValue(fv, f, e.Type)
+		case CodecProperty:
+			if p, ok := e.Properties[f.name]; ok {
+				err = decodeInstances(fv, f, propertyInstancesParts(p))
+			}
+		case CodecRelationship:
+			if r, ok := e.Relationships[f.name]; ok {
+				err = decodeInstances(fv, f, relationshipInstancesParts(r))
+			}
+		case CodecGeoProperty:
+			if target := e.geoPropertyField(f.name); target != nil && *target != nil {
+				err = decodeAttribute(fv, f, geoPropertyParts(*target))
+			} else if g, ok := e.GeoProperties[f.name]; ok {
+				err = decodeInstances(fv, f, geoPropertyInstancesParts(g))
+			}
+		default:
+			return errors.Wrapf(ErrCodecInvalidTag, "field %s: %s is not allowed in an Entity", rv.Type().Field(f.index).Name, f.kind)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// codecFields returns the annotated fields of the struct type
+func codecFields(t reflect.Type) ([]codecField, error) {
+	fields := []codecField{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup(codecTag)
+		if !ok || tag == "-" || sf.PkgPath != "" {
+			continue
+		}
+
+		items := strings.Split(tag, ",")
+		if len(items) < 2 {
+			return nil, errors.Wrapf(ErrCodecInvalidTag, "field %s: missing kind in %q", sf.Name, tag)
+		}
+		f := codecField{index: i, name: items[0], kind: items[1]}
+		for _, option := range items[2:] {
+			switch {
+			case option == "omitempty":
+				f.omitEmpty = true
+			case strings.HasPrefix(option, "unitCode=") && f.kind == CodecProperty:
+				unitCode := strings.TrimPrefix(option, "unitCode=")
+				f.unitCode = &unitCode
+			default:
+				return nil, errors.Wrapf(ErrCodecInvalidTag, "field %s: unknown option %q", sf.Name, option)
+			}
+		}
+
+		switch f.kind {
+		case CodecProperty, CodecRelationship, CodecGeoProperty:
+			if f.name == "" {
+				return nil, errors.Wrapf(ErrCodecInvalidTag, "field %s: missing attribute name", sf.Name)
+			}
+		case CodecID, CodecType, CodecValue, CodecObject, CodecObservedAt, CodecDatasetID, CodecUnitCode:
+		default:
+			return nil, errors.Wrapf(ErrCodecInvalidTag, "field %s: unknown kind %q", sf.Name, f.kind)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// attributeStruct tells if the type maps an Attribute with its metadata,
+// rather than the value of the Attribute
+func attributeStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		kind := strings.Split(t.Field(i).Tag.Get(codecTag), ",")
+		if len(kind) > 1 && (kind[1] == CodecValue || kind[1] == CodecObject) {
+			return true
+		}
+	}
+	return false
+}
+
+// absent tells if the field must not be mapped
+func absent(fv reflect.Value, f codecField) bool {
+	switch fv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if fv.IsNil() {
+			return true
+		}
+	}
+	return f.omitEmpty && fv.IsZero()
+}
+
+func encodeString(fv reflect.Value, f codecField) (string, error) {
+	if absent(fv, f) {
+		return "", nil
+	}
+	fv = reflect.Indirect(fv)
+	if fv.Kind() != reflect.String {
+		return "", errors.Wrapf(ErrCodecWrongType, "%s must be a string, not %s", f.kind, fv.Type())
+	}
+	return fv.String(), nil
+}
+
+// instancesStruct tells if the type maps the instances of a multi-attribute,
+// i.e. it is a slice of Attribute structs
+func instancesStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Slice && attributeStruct(t.Elem())
+}
+
+// encodeInstances returns the members of the instances of the Attribute mapped by the field
+func encodeInstances(fv reflect.Value, f codecField) ([]attributeParts, error) {
+	if !instancesStruct(fv.Type()) {
+		parts, ok, err := encodeAttribute(fv, f)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return []attributeParts{parts}, nil
+	}
+
+	if absent(fv, f) {
+		return nil, nil
+	}
+	fv = reflect.Indirect(fv)
+	instances := make([]attributeParts, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		parts, ok, err := encodeAttribute(fv.Index(i), f)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			instances = append(instances, parts)
+		}
+	}
+	return instances, nil
+}
+
+// encodeAttribute returns the members of the Attribute mapped by the field,
+// false when the Attribute is absent
+func encodeAttribute(fv reflect.Value, f codecField) (attributeParts, bool, error) {
+	parts := attributeParts{unitCode: f.unitCode}
+	if absent(fv, f) {
+		return parts, false, nil
+	}
+	fv = reflect.Indirect(fv)
+
+	if !attributeStruct(fv.Type()) {
+		if f.kind == CodecRelationship {
+			object, err := encodeString(fv, f)
+			return attributeParts{object: object}, err == nil && object != "", err
+		}
+		parts.value = fv.Interface()
+		return parts, true, nil
+	}
+
+	fields, err := codecFields(fv.Type())
+	if err != nil {
+		return parts, false, err
+	}
+	parts.properties = Properties{}
+	parts.relationships = Relationships{}
+	parts.geoProperties = GeoProperties{}
+	for _, sub := range fields {
+		sv := fv.Field(sub.index)
+		switch sub.kind {
+		case CodecValue:
+			if f.kind == CodecRelationship {
+				return parts, false, errors.Wrapf(ErrCodecInvalidTag, "attribute %s: Relationships have an object, not a value", f.name)
+			}
+			if absent(sv, sub) {
+				return parts, false, nil
+			}
+			parts.value = reflect.Indirect(sv).Interface()
+		case CodecObject:
+			if f.kind != CodecRelationship {
+				return parts, false, errors.Wrapf(ErrCodecInvalidTag, "attribute %s: only Relationships have an object", f.name)
+			}
+			object, err := encodeString(sv, sub)
+			if err != nil {
+				return parts, false, err
+			}
+			if object == "" {
+				return parts, false, nil
+			}
+			parts.object = object
+		case CodecObservedAt:
+			if absent(sv, sub) {
+				continue
+			}
+			sv = reflect.Indirect(sv)
+			if sv.Type() != timeType {
+				return parts, false, errors.Wrapf(ErrCodecWrongType, "observedAt must be a time.Time, not %s", sv.Type())
+			}
+			observedAt := sv.Interface().(time.Time)
+			if !observedAt.IsZero() {
+				parts.observedAt = &observedAt
+			}
+		case CodecDatasetID, CodecUnitCode:
+			s, err := encodeString(sv, sub)
+			if err != nil {
+				return parts, false, err
+			}
+			if s == "" {
+				continue
+			}
+			if sub.kind == CodecDatasetID {
+				parts.datasetID = &s
+			} else if f.kind == CodecProperty {
+				parts.unitCode = &s
+			} else {
+				return parts, false, errors.Wrapf(ErrCodecInvalidTag, "attribute %s: only Properties have a unitCode", f.name)
+			}
+		case CodecProperty, CodecRelationship, CodecGeoProperty:
+			instances, err := encodeInstances(sv, sub)
+			if err != nil {
+				return parts, false, err
+			}
+			for _, subParts := range instances {
+				switch sub.kind {
+				case CodecProperty:
+					parts.properties[sub.name] = append(parts.properties[sub.name], subParts.property())
+				case CodecRelationship:
+					parts.relationships[sub.name] = append(parts.relationships[sub.name], subParts.relationship())
+				case CodecGeoProperty:
+					g, err := subParts.geoProperty(sub.name)
+					if err != nil {
+						return parts, false, err
+					}
+					parts.geoProperties[sub.name] = append(parts.geoProperties[sub.name], *g)
+				}
+			}
+		default:
+			return parts, false, errors.Wrapf(ErrCodecInvalidTag, "attribute %s: %s is not allowed in an Attribute", f.name, sub.kind)
+		}
+	}
+
+	// Do not return empty maps
+	if len(parts.properties) == 0 {
+		parts.properties = nil
+	}
+	if len(parts.relationships) == 0 {
+		parts.relationships = nil
+	}
+	if len(parts.geoProperties) == 0 {
+		parts.geoProperties = nil
+	}
+	return parts, true, nil
+}
+
+func (parts attributeParts) property() Property {
+	return Property{
+		Value:         parts.value,
+		Properties:    parts.properties,
+		Relationships: parts.relationships,
+		GeoProperties: parts.geoProperties,
+		ObservedAt:    parts.observedAt,
+		UnitCode:      parts.unitCode,
+		DatasetID:     parts.datasetID,
+	}
+}
+
+func (parts attributeParts) relationship() Relationship {
+	return Relationship{
+		Object:        parts.object,
+		Properties:    parts.properties,
+		Relationships: parts.relationships,
+		GeoProperties: parts.geoProperties,
+		ObservedAt:    parts.observedAt,
+		DatasetID:     parts.datasetID,
+	}
+}
+
+func (parts attributeParts) geoProperty(name string) (*GeoProperty, error) {
+	var geometry *geojson.Geometry
+	switch v := parts.value.(type) {
+	case geojson.Geometry:
+		geometry = &v
+	case *geojson.Geometry:
+		geometry = v
+	default:
+		return nil, errors.Wrapf(ErrCodecWrongType, "GeoProperty %s must be a geojson.Geometry, not %T", name, parts.value)
+	}
+	return &GeoProperty{
+		Value:         geometry,
+		Properties:    parts.properties,
+		Relationships: parts.relationships,
+		GeoProperties: parts.geoProperties,
+		ObservedAt:    parts.observedAt,
+		DatasetID:     parts.datasetID,
+	}, nil
+}
+
+func propertyInstancesParts(instances PropertyInstances) []attributeParts {
+	parts := make([]attributeParts, 0, len(instances))
+	for i := range instances {
+		parts = append(parts, propertyParts(&instances[i]))
+	}
+	return parts
+}
+
+func relationshipInstancesParts(instances RelationshipInstances) []attributeParts {
+	parts := make([]attributeParts, 0, len(instances))
+	for i := range instances {
+		parts = append(parts, relationshipParts(&instances[i]))
+	}
+	return parts
+}
+
+func geoPropertyInstancesParts(instances GeoPropertyInstances) []attributeParts {
+	parts := make([]attributeParts, 0, len(instances))
+	for i := range instances {
+		parts = append(parts, geoPropertyParts(&instances[i]))
+	}
+	return parts
+}
+
+func propertyParts(p *Property) attributeParts {
+	return attributeParts{
+		value:         p.Value,
+		observedAt:    p.ObservedAt,
+		datasetID:     p.DatasetID,
+		unitCode:      p.UnitCode,
+		properties:    p.Properties,
+		relationships: p.Relationships,
+		geoProperties: p.GeoProperties,
+	}
+}
+
+func relationshipParts(r *Relationship) attributeParts {
+	return attributeParts{
+		object:        r.Object,
+		observedAt:    r.ObservedAt,
+		datasetID:     r.DatasetID,
+		properties:    r.Properties,
+		relationships: r.Relationships,
+		geoProperties: r.GeoProperties,
+	}
+}
+
+func geoPropertyParts(g *GeoProperty) attributeParts {
+	return attributeParts{
+		value:         g.Value,
+		observedAt:    g.ObservedAt,
+		datasetID:     g.DatasetID,
+		properties:    g.Properties,
+		relationships: g.Relationships,
+		geoProperties: g.GeoProperties,
+	}
+}
+
+// decodeInstances fills the field with the members of the instances of the Attribute.
+// A field mapping a single instance gets the default one, or the only one.
+func decodeInstances(fv reflect.Value, f codecField, instances []attributeParts) error {
+	if instancesStruct(fv.Type()) {
+		fv = allocate(fv)
+		items := reflect.MakeSlice(fv.Type(), len(instances), len(instances))
+		for i, parts := range instances {
+			err := decodeAttribute(items.Index(i), f, parts)
+			if err != nil {
+				return err
+			}
+		}
+		fv.Set(items)
+		return nil
+	}
+
+	for _, parts := range instances {
+		if parts.datasetID == nil || *parts.datasetID == "" {
+			return decodeAttribute(fv, f, parts)
+		}
+	}
+	switch len(instances) {
+	case 0:
+		return nil
+	case 1:
+		return decodeAttribute(fv, f, instances[0])
+	}
+	return errors.Wrapf(ErrCodecWrongType, "%s %s has several instances, it must be mapped to a slice", f.kind, f.name)
+}
+
+// decodeAttribute fills the field with the members of the Attribute
+func decodeAttribute(fv reflect.Value, f codecField, parts attributeParts) error {
+	if !attributeStruct(fv.Type()) {
+		if f.kind == CodecRelationship {
+			return decodeValue(fv, f, parts.object)
+		}
+		return decodeValue(fv, f, parts.value)
+	}
+
+	fv = allocate(fv)
+	fields, err := codecFields(fv.Type())
+	if err != nil {
+		return err
+	}
+	for _, sub := range fields {
+		sv := fv.Field(sub.index)
+		var err error
+		switch sub.kind {
+		case CodecValue:
+			err = decodeValue(sv, f, parts.value)
+		case CodecObject:
+			err = decodeValue(sv, f, parts.object)
+		case CodecObservedAt:
+			if parts.observedAt != nil {
+				err = decodeValue(sv, sub, *parts.observedAt)
+			}
+		case CodecDatasetID:
+			if parts.datasetID != nil {
+				err = decodeValue(sv, sub, *parts.datasetID)
+			}
+		case CodecUnitCode:
+			if parts.unitCode != nil {
+				err = decodeValue(sv, sub, *parts.unitCode)
+			}
+		case CodecProperty:
+			if p, ok := parts.properties[sub.name]; ok {
+				err = decodeInstances(sv, sub, propertyInstancesParts(p))
+			}
+		case CodecRelationship:
+			if r, ok := parts.relationships[sub.name]; ok {
+				err = decodeInstances(sv, sub, relationshipInstancesParts(r))
+			}
+		case CodecGeoProperty:
+			if g, ok := parts.geoProperties[sub.name]; ok {
+				err = decodeInstances(sv, sub, geoPropertyInstancesParts(g))
+			}
+		default:
+			return errors.Wrapf(ErrCodecInvalidTag, "attribute %s: %s is not allowed in an Attribute", f.name, sub.kind)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeValue sets the field to the value, converting it through its JSON representation
+// when the types differ, e.g. from the map decoded for a structured value
+func decodeValue(fv reflect.Value, f codecField, value any) error {
+	if value == nil {
+		return nil
+	}
+	fv = allocate(fv)
+
+	rv := reflect.ValueOf(value)
+	if rv.Type().AssignableTo(fv.Type()) {
+		fv.Set(rv)
+		return nil
+	}
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Type().AssignableTo(fv.Type()) {
+		fv.Set(rv.Elem())
+		return nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrapf(ErrCodecWrongType, "%s %s: %s", f.kind, f.name, err)
+	}
+	err = json.Unmarshal(b, fv.Addr().Interface())
+	if err != nil {
+		return errors.Wrapf(ErrCodecWrongType, "%s %s: %s", f.kind, f.name, err)
+	}
+	return nil
+}
+
+// allocate returns the value pointed by the field, allocating the nil pointers
+func allocate(fv reflect.Value) reflect.Value {
+	for fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		fv = fv.Elem()
+	}
+	return fv
+}
@@ -0,0 +1,172 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/philiphil/geojson"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTemperature struct {
+	Value      float64   `ngsi:",value"`
+	ObservedAt time.Time `ngsi:",observedAt"`
+	DatasetID  string    `ngsi:",datasetId"`
+	Accuracy   *float64  `ngsi:"accuracy,property"`
+	Sensor     string    `ngsi:"refSensor,relationship"`
+}
+
+type testRoom struct {
+	ID          string            `ngsi:",id"`
+	Type        string            `ngsi:"Room,type"`
+	Name        string            `ngsi:"name,property,omitempty"`
+	Temperature testTemperature   `ngsi:"temperature,property,unitCode=CEL"`
+	Tags        []string          `ngsi:"tags,property"`
+	Size        *testSize         `ngsi:"size,property"`
+	Building    string            `ngsi:"refBuilding,relationship"`
+	Location    *geojson.Geometry `ngsi:"location,geoproperty"`
+	Notes       string
+}
+
+type testSize struct {
+	Width  float64 `json:"width"`
+	Length float64 `json:"length"`
+}
+
+func TestMarshalEntityStruct(t *testing.T) {
+	observedAt := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	accuracy := 0.5
+	room := testRoom{
+		ID: "urn:ngsi-ld:Room:1",
+		Temperature: testTemperature{
+			Value:      21.5,
+			ObservedAt: observedAt,
+			DatasetID:  "urn:ngsi-ld:Dataset:sensor",
+			Accuracy:   &accuracy,
+			Sensor:     "urn:ngsi-ld:Sensor:1",
+		},
+		Size:     &testSize{Width: 4, Length: 5},
+		Building: "urn:ngsi-ld:Building:1",
+		Location: geojson.NewPointGeometry([]float64{11.25, 43.77}),
+		Notes:    "not mapped",
+	}
+
+	e, err := model.MarshalEntity(&room)
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:ngsi-ld:Room:1", e.ID)
+	assert.Equal(t, "Room", e.Type)
+	assert.NoError(t, e.Validate(true))
+
+	celsius := "CEL"
+	datasetID := "urn:ngsi-ld:Dataset:sensor"
+	assert.Equal(t, model.Properties{
+		"temperature": {{
+			Value:      21.5,
+			ObservedAt: &observedAt,
+			UnitCode:   &celsius,
+			DatasetID:  &datasetID,
+			Properties: model.Properties{
+				"accuracy": {{Value: 0.5}},
+			},
+			Relationships: model.Relationships{
+				"refSensor": {{Object: "urn:ngsi-ld:Sensor:1"}},
+			},
+		}},
+		"size": {{Value: testSize{Width: 4, Length: 5}}},
+	}, e.Properties)
+	assert.Equal(t, model.Relationships{"refBuilding": {{Object: "urn:ngsi-ld:Building:1"}}}, e.Relationships)
+	assert.Equal(t, room.Location, e.Location.Value)
+
+	// Decode the Entity as received from the Context Broker
+	b, err := json.Marshal(e)
+	assert.NoError(t, err)
+	received := model.Entity{}
+	assert.NoError(t, json.Unmarshal(b, &received))
+
+	decoded := testRoom{}
+	assert.NoError(t, model.UnmarshalEntity(&received, &decoded))
+	room.Notes = ""
+	room.Type = "Room"
+	assert.Equal(t, room, decoded)
+}
+
+func TestMarshalEntityGeoProperties(t *testing.T) {
+	type servedArea struct {
+		Value     *geojson.Geometry `ngsi:",value"`
+		DatasetID string            `ngsi:",datasetId,omitempty"`
+		Entrance  *geojson.Geometry `ngsi:"entrance,geoproperty"`
+	}
+	type stop struct {
+		ID          string            `ngsi:",id"`
+		Type        string            `ngsi:"BusStop,type"`
+		Location    *geojson.Geometry `ngsi:"location,geoproperty"`
+		Destination *geojson.Geometry `ngsi:"destination,geoproperty"`
+		ServedArea  []servedArea      `ngsi:"servedArea,geoproperty"`
+	}
+
+	s := stop{
+		ID:          "urn:ngsi-ld:BusStop:1",
+		Type:        "BusStop",
+		Location:    geojson.NewPointGeometry([]float64{11.25, 43.77}),
+		Destination: geojson.NewPointGeometry([]float64{11.26, 43.78}),
+		ServedArea: []servedArea{
+			{
+				Value:    geojson.NewPolygonGeometry([][][]float64{{{11, 43}, {12, 43}, {12, 44}, {11, 43}}}),
+				Entrance: geojson.NewPointGeometry([]float64{11.5, 43.5}),
+			},
+			{
+				Value:     geojson.NewPolygonGeometry([][][]float64{{{11, 43}, {13, 43}, {13, 45}, {11, 43}}}),
+				DatasetID: "urn:ngsi-ld:Dataset:night",
+			},
+		},
+	}
+	e, err := model.MarshalEntity(s)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Validate(true))
+	assert.NotNil(t, e.Location)
+	assert.Len(t, e.GeoProperties["destination"], 1)
+	assert.Len(t, e.GeoProperties["servedArea"], 2)
+	assert.Contains(t, e.GeoProperties["servedArea"][0].GeoProperties, "entrance")
+
+	b, err := json.Marshal(e)
+	assert.NoError(t, err)
+	received := model.Entity{}
+	assert.NoError(t, json.Unmarshal(b, &received))
+
+	decoded := stop{}
+	assert.NoError(t, model.UnmarshalEntity(&received, &decoded))
+	assert.Equal(t, s, decoded)
+}
+
+func TestMarshalEntityStructErrors(t *testing.T) {
+	_, err := model.MarshalEntity("urn:ngsi-ld:Room:1")
+	assert.ErrorIs(t, err, model.ErrCodecInvalidTarget)
+
+	_, err = model.MarshalEntity(struct {
+		Temperature float64 `ngsi:"temperature"`
+	}{})
+	assert.ErrorIs(t, err, model.ErrCodecInvalidTag)
+
+	_, err = model.MarshalEntity(struct {
+		Location string `ngsi:"location,geoproperty"`
+	}{Location: "Florence"})
+	assert.ErrorIs(t, err, model.ErrCodecWrongType)
+
+	_, err = model.MarshalEntity(struct {
+		Building int `ngsi:"refBuilding,relationship"`
+	}{Building: 1})
+	assert.ErrorIs(t, err, model.ErrCodecWrongType)
+
+	err = model.UnmarshalEntity(&model.Entity{}, testRoom{})
+	assert.ErrorIs(t, err, model.ErrCodecInvalidTarget)
+
+	decoded := testRoom{}
+	err = model.UnmarshalEntity(&model.Entity{
+		ID:         "urn:ngsi-ld:Room:1",
+		Type:       "Room",
+		Properties: model.Properties{"tags": {{Value: "kitchen"}}},
+	}, &decoded)
+	assert.ErrorIs(t, err, model.ErrCodecWrongType)
+}
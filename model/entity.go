@@ -4,36 +4,75 @@ import (
 	"encoding/json"
 	"reflect"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/pkg/errors"
 )
 
 // Properties is a helper type, defines a set of Properties, identified by a string
-type Properties map[string]Property
+type Properties = AttributeMap[Property]
 
 // Relationships is a helper type, defines a set of Relationships, identified by a string
-type Relationships map[string]Relationship
+type Relationships = AttributeMap[Relationship]
+
+// GeoProperties is a helper type, defines a set of GeoProperties, identified by a string
+type GeoProperties = AttributeMap[GeoProperty]
+
+// LanguageProperties is a helper type, defines a set of LanguageProperties, identified by a string
+type LanguageProperties = AttributeMap[LanguageProperty]
+
+// VocabProperties is a helper type, defines a set of VocabProperties, identified by a string
+type VocabProperties = AttributeMap[VocabProperty]
+
+// JSONProperties is a helper type, defines a set of JsonProperties, identified by a string
+type JSONProperties = AttributeMap[JSONProperty]
+
+// ListProperties is a helper type, defines a set of ListProperties, identified by a string
+type ListProperties = AttributeMap[ListProperty]
+
+// ListRelationships is a helper type, defines a set of ListRelationships, identified by a string
+type ListRelationships = AttributeMap[ListRelationship]
+
+// Attribute is implemented by every kind of Attribute, e.g. *Property
+type Attribute interface {
+	json.Marshaler
+	Validatable
+	Type() string
+}
 
 // Entity is the top-level abstraction of the user domain and thus it can represent anything.
 // Entities have mandatory type and id and a number of Attributes
 // https://github.com/FIWARE/context.Orion-LD/blob/develop/doc/manuals-ld/entities-and-attributes.md
 type Entity struct {
-	ID               string        `json:"id"`                         // ID of the entity used to identify the single entity
-	Type             string        `json:"type"`                       // Type of the entity used for categorization
-	Properties       Properties    `json:"-"`                          // Values that define the entity
-	Relationships    Relationships `json:"-"`                          // Links to other entities
-	Location         *GeoProperty  `json:"location,omitempty"`         // Position of the Entity
-	ObservationSpace *GeoProperty  `json:"observationSpace,omitempty"` // Area observable by the Entity (e.g. a camera)
-	OperationSpace   *GeoProperty  `json:"operationSpace,omitempty"`   // Area operable by the Entity (e.g. a sprinkler)
+	ID                 string             `json:"id"`                   // ID of the entity used to identify the single entity
+	Type               string             `json:"type"`                 // Type of the entity used for categorization
+	Scope              []string           `json:"-"`                    // Hierarchical scopes of the entity, e.g. /Italy/Tuscany
+	Properties         Properties         `json:"-"`                    // Values that define the entity
+	Relationships      Relationships      `json:"-"`                    // Links to other entities
+	LanguageProperties LanguageProperties `json:"-"`                    // Strings in several languages
+	VocabProperties    VocabProperties    `json:"-"`                    // Terms of the @context
+	JSONProperties     JSONProperties     `json:"-"`                    // JSON values not interpreted as JSON-LD
+	ListProperties     ListProperties     `json:"-"`                    // Ordered lists of values
+	ListRelationships  ListRelationships  `json:"-"`                    // Links to ordered lists of entities
+	GeoProperties      GeoProperties      `json:"-"`                    // Geometries other than the fields below, e.g. a destination, or the ones with several instances
+	Location           *GeoProperty       `json:"-"`                    // Position of the Entity
+	ObservationSpace   *GeoProperty       `json:"-"`                    // Area observable by the Entity (e.g. a camera)
+	OperationSpace     *GeoProperty       `json:"-"`                    // Area operable by the Entity (e.g. a sprinkler)
+	CreatedAt          *time.Time         `json:"createdAt,omitempty"`  // Creation time, set by the Context Broker
+	ModifiedAt         *time.Time         `json:"modifiedAt,omitempty"` // Last modification time, set by the Context Broker
+	DeletedAt          *time.Time         `json:"deletedAt,omitempty"`  // Deletion time, set by the Context Broker
 }
 
-const timeRFC3339Micro = "2006-01-02T15:04:05.999999Z07:00"
+// TimeRFC3339Micro is the layout of the NGSI-LD DateTime values, in microseconds
+const TimeRFC3339Micro = "2006-01-02T15:04:05.999999Z07:00"
 
 func (e Entity) MarshalJSON() ([]byte, error) {
 	data := map[string]any{}
 
 	data["type"] = e.Type
 	data["id"] = e.ID
+	marshalScope(data, e.Scope)
 	if e.Location != nil {
 		data["location"] = e.Location
 	}
@@ -43,14 +82,8 @@ func (e Entity) MarshalJSON() ([]byte, error) {
 	if e.OperationSpace != nil {
 		data["operationSpace"] = e.OperationSpace
 	}
-
-	for k, v := range e.Properties {
-		data[k] = v
-	}
-
-	for k, v := range e.Relationships {
-		data[k] = v
-	}
+	marshalSystemAttributes(data, e.CreatedAt, e.ModifiedAt, e.DeletedAt)
+	e.attributes().marshal(data)
 
 	return json.Marshal(data)
 }
@@ -87,46 +120,28 @@ func (e *Entity) UnmarshalJSON(b []byte) error {
 		}
 	}
 
-	// Third pass - partial decode to discover the type of the attribute
-	type Attribute struct {
-		Type string `json:"type,omitempty"`
+	scope, err := unmarshalScope(jsonValues)
+	if err != nil {
+		return err
 	}
+	d.Scope = scope
 
-	d.Relationships = Relationships{}
-	d.Properties = Properties{}
-
-	for k, v := range jsonValues {
-		a := Attribute{}
-		err := json.Unmarshal(v, &a)
-		if err != nil {
-			return ErrInvalidEntity(errors.Wrapf(err, "cannot unmarshal attribute %s", k))
-		}
-
-		// Fourth pass - decode according to type
-		switch a.Type {
-		case "Relationship":
-			r := Relationship{}
-			err = json.Unmarshal(v, &r)
-			if err != nil {
-				return ErrInvalidEntity(errors.Wrapf(err, "cannot unmarshal relationship %s", k))
-			}
-			d.Relationships[k] = r
-		case "Property":
-			p := Property{}
-			err = json.Unmarshal(v, &p)
-			if err != nil {
-				return ErrInvalidEntity(errors.Wrapf(err, "cannot unmarshal property %s", k))
-			}
-			d.Properties[k] = p
-		}
+	// Third pass - decode the attributes according to their type
+	err = (*Entity)(&d).attributes().decode(jsonValues)
+	if err != nil {
+		return ErrInvalidEntity(err)
 	}
 
-	// Do not return empty maps
-	if len(d.Properties) == 0 {
-		d.Properties = nil
+	// The well-known GeoProperties have their own field, unless they have several instances
+	for _, name := range []string{"location", "observationSpace", "operationSpace"} {
+		if instances := d.GeoProperties[name]; len(instances) == 1 {
+			g := instances[0]
+			*(*Entity)(&d).geoPropertyField(name) = &g
+			delete(d.GeoProperties, name)
+		}
 	}
-	if len(d.Relationships) == 0 {
-		d.Relationships = nil
+	if len(d.GeoProperties) == 0 {
+		d.GeoProperties = nil
 	}
 
 	// Assign fields to pointed structure
@@ -142,14 +157,113 @@ func (e *Entity) Validate(strictness bool) ValidationResult {
 	if len(e.Type) == 0 {
 		return ErrEntityMissingType
 	}
+	for _, scope := range e.Scope {
+		if !validScope(scope) {
+			return errors.Wrapf(ErrEntityInvalidScope, "scope: %s", scope)
+		}
+	}
+	for _, name := range e.geoPropertyFieldNames() {
+		if _, ok := e.GeoProperties[name]; ok {
+			return errors.Wrapf(ErrEntityTermCollision, "term: %s", name)
+		}
+	}
+	for _, g := range []*GeoProperty{e.Location, e.ObservationSpace, e.OperationSpace} {
+		if g != nil {
+			err := g.Validate(strictness)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return e.attributes().validate(strictness)
+}
 
-	for _, x := range e.Properties {
-		err := x.Validate(strictness)
-		if err != nil {
-			return err
+// ValidateCreation checks the Entity to be created: besides Validate(true), it refuses
+// the timestamps set by the Context Broker, on the Entity and on any of its Attributes.
+// Other writes accept them, so that retrieved Entities can be sent back.
+func (e *Entity) ValidateCreation() ValidationResult {
+	err := e.Validate(true)
+	if err != nil {
+		return err
+	}
+	if systemAttributesSet(e.CreatedAt, e.ModifiedAt, e.DeletedAt) || e.attributes().hasSystemAttributes() {
+		return ErrSystemAttributeNotAllowed
+	}
+	for _, g := range []*GeoProperty{e.Location, e.ObservationSpace, e.OperationSpace} {
+		if g != nil && g.members().hasSystemAttributes() {
+			return ErrSystemAttributeNotAllowed
 		}
 	}
-	for _, x := range e.Relationships {
+	return nil
+}
+
+// attributes points to every set of Attributes of the Entity
+func (e *Entity) attributes() attributeSet {
+	return attributeSet{
+		properties:         &e.Properties,
+		relationships:      &e.Relationships,
+		geoProperties:      &e.GeoProperties,
+		languageProperties: &e.LanguageProperties,
+		vocabProperties:    &e.VocabProperties,
+		jsonProperties:     &e.JSONProperties,
+		listProperties:     &e.ListProperties,
+		listRelationships:  &e.ListRelationships,
+	}
+}
+
+// marshalScope stores the scopes of an entity, a single scope is a string
+func marshalScope(data map[string]any, scope []string) {
+	if len(scope) == 1 {
+		data["scope"] = scope[0]
+	} else if len(scope) > 1 {
+		data["scope"] = scope
+	}
+}
+
+// unmarshalScope extracts the scopes of an entity from its members,
+// a single scope is a string
+func unmarshalScope(jsonValues map[string]json.RawMessage) ([]string, error) {
+	raw, ok := jsonValues["scope"]
+	if !ok {
+		return nil, nil
+	}
+	delete(jsonValues, "scope")
+
+	scope := []string{}
+	if err := json.Unmarshal(raw, &scope); err != nil {
+		single := ""
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return nil, ErrEntityInvalidScope
+		}
+		scope = []string{single}
+	}
+	return scope, nil
+}
+
+// validScope tells if the scope is a path of non-empty levels, e.g. /Italy/Tuscany.
+// The levels can't hold spaces, nor the characters that have a meaning in scope queries.
+func validScope(scope string) bool {
+	if !strings.HasPrefix(scope, "/") {
+		return false
+	}
+	for _, level := range strings.Split(scope[1:], "/") {
+		if level == "" || strings.ContainsAny(level, "#+;|,()") || strings.IndexFunc(level, unicode.IsSpace) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// marshalAttributes adds the set of Attributes to the JSON object
+func marshalAttributes[M ~map[string]S, S any](data map[string]any, attributes M) {
+	for k, v := range attributes {
+		data[k] = v
+	}
+}
+
+// validateAttributes checks every Attribute of the set
+func validateAttributes[M ~map[string]S, S Validatable](attributes M, strictness bool) ValidationResult {
+	for _, x := range attributes {
 		err := x.Validate(strictness)
 		if err != nil {
 			return err
@@ -12,15 +12,55 @@ var (
 type ErrInvalidProperty error
 
 var (
-	ErrPropertyWrongType    ErrInvalidProperty = errors.New(`Property must have "Property" type`)
-	ErrPropertyMissingValue ErrInvalidProperty = errors.New(`Property must have a "value" field`)
+	ErrPropertyWrongType      ErrInvalidProperty = errors.New(`Property must have "Property" type`)
+	ErrPropertyMissingValue   ErrInvalidProperty = errors.New(`Property must have a "value" field`)
+	ErrPropertyValueWrongType ErrInvalidProperty = errors.New(`Property value has not the requested type`)
+)
+
+type ErrInvalidLanguageProperty error
+
+var (
+	ErrLanguagePropertyWrongType  ErrInvalidLanguageProperty = errors.New(`LanguageProperty must have "LanguageProperty" type`)
+	ErrLanguagePropertyMissingMap ErrInvalidLanguageProperty = errors.New(`LanguageProperty must have a "languageMap" field`)
+)
+
+type ErrInvalidVocabProperty error
+
+var (
+	ErrVocabPropertyWrongType    ErrInvalidVocabProperty = errors.New(`VocabProperty must have "VocabProperty" type`)
+	ErrVocabPropertyMissingVocab ErrInvalidVocabProperty = errors.New(`VocabProperty must have a "vocab" field`)
+	ErrVocabPropertyInvalidVocab ErrInvalidVocabProperty = errors.New(`VocabProperty vocab must be a term or a list of terms`)
+)
+
+type ErrInvalidJSONProperty error
+
+var (
+	ErrJSONPropertyWrongType   ErrInvalidJSONProperty = errors.New(`JsonProperty must have "JsonProperty" type`)
+	ErrJSONPropertyMissingJSON ErrInvalidJSONProperty = errors.New(`JsonProperty must have a "json" field`)
+)
+
+type ErrInvalidListProperty error
+
+var (
+	ErrListPropertyWrongType        ErrInvalidListProperty = errors.New(`ListProperty must have "ListProperty" type`)
+	ErrListPropertyMissingValueList ErrInvalidListProperty = errors.New(`ListProperty must have a "valueList" field`)
+)
+
+type ErrInvalidListRelationship error
+
+var (
+	ErrListRelationshipWrongType         ErrInvalidListRelationship = errors.New(`ListRelationship must have "ListRelationship" type`)
+	ErrListRelationshipMissingObjectList ErrInvalidListRelationship = errors.New(`ListRelationship must have an "objectList" field`)
+	ErrListRelationshipInvalidObjectList ErrInvalidListRelationship = errors.New(`ListRelationship objectList must hold the URIs of the objects`)
 )
 
 type ErrInvalidEntity error
 
 var (
-	ErrEntityMissingType ErrInvalidEntity = errors.New(`Entity must have a type`)
-	ErrEntityMissingID   ErrInvalidEntity = errors.New(`Entity must have an ID`)
+	ErrEntityMissingType   ErrInvalidEntity = errors.New(`Entity must have a type`)
+	ErrEntityMissingID     ErrInvalidEntity = errors.New(`Entity must have an ID`)
+	ErrEntityTermCollision ErrInvalidEntity = errors.New(`Attributes of the Entity map to the same term`)
+	ErrEntityInvalidScope  ErrInvalidEntity = errors.New(`Entity scope must be a path like /Italy/Tuscany, or a list of them`)
 )
 
 type ErrInvalidGeoProperty error
@@ -30,3 +70,73 @@ var (
 	ErrGeoPropertyMissingValue ErrInvalidGeoProperty = errors.New(`GeoProperty must have a "value" field`)
 	ErrGeoPropertyInvalidValue ErrInvalidGeoProperty = errors.New(`GeoProperty value must be a valid GeoJson geometry except GeometryCollection`)
 )
+
+type ErrInvalidSystemAttribute error
+
+var (
+	ErrSystemAttributeNotAllowed ErrInvalidSystemAttribute = errors.New(`createdAt, modifiedAt and deletedAt are set by the Context Broker`)
+)
+
+type ErrInvalidMultiAttribute error
+
+var (
+	ErrAttributeMissingInstances   ErrInvalidMultiAttribute = errors.New(`Attribute must have at least one instance`)
+	ErrAttributeDuplicateDataset   ErrInvalidMultiAttribute = errors.New(`instances of an Attribute must have different datasetId`)
+	ErrAttributeMixedInstanceTypes ErrInvalidMultiAttribute = errors.New(`instances of an Attribute must have the same type`)
+)
+
+type ErrInvalidEntityMapping error
+
+var (
+	ErrCodecInvalidTarget ErrInvalidEntityMapping = errors.New(`Entities map only to structs`)
+	ErrCodecInvalidTag    ErrInvalidEntityMapping = errors.New(`invalid ngsi tag`)
+	ErrCodecWrongType     ErrInvalidEntityMapping = errors.New(`field has not the type of the Attribute`)
+)
+
+type ErrInvalidGeoQuery error
+
+var (
+	ErrGeoQueryMissingGeometry    ErrInvalidGeoQuery = errors.New(`GeoQuery must have a "geometry" field`)
+	ErrGeoQueryInvalidGeometry    ErrInvalidGeoQuery = errors.New(`GeoQuery geometry must be a GeoJson geometry type except GeometryCollection`)
+	ErrGeoQueryMissingCoordinates ErrInvalidGeoQuery = errors.New(`GeoQuery must have a "coordinates" field`)
+	ErrGeoQueryInvalidGeorel      ErrInvalidGeoQuery = errors.New(`GeoQuery must have a valid "georel" field`)
+)
+
+type ErrInvalidEntityFragment error
+
+var (
+	ErrEntityFragmentEmpty ErrInvalidEntityFragment = errors.New(`Entity fragment must have at least one Attribute`)
+)
+
+type ErrInvalidSubscription error
+
+var (
+	ErrSubscriptionMissingTarget         ErrInvalidSubscription = errors.New(`Subscription must have "entities" or "watchedAttributes"`)
+	ErrSubscriptionInvalidEntitySelector ErrInvalidSubscription = errors.New(`Subscription entities must have a type and at most one of "id" and "idPattern"`)
+	ErrSubscriptionMissingNotification   ErrInvalidSubscription = errors.New(`Subscription must have a "notification" field`)
+	ErrSubscriptionInvalidEndpoint       ErrInvalidSubscription = errors.New(`Subscription notification endpoint must be an absolute URI`)
+	ErrSubscriptionInvalidAccept         ErrInvalidSubscription = errors.New(`Subscription notification endpoint accepts only JSON, JSON-LD or GeoJSON`)
+	ErrSubscriptionInvalidFormat         ErrInvalidSubscription = errors.New(`Subscription notification format must be "normalized" or "keyValues"`)
+	ErrSubscriptionInvalidTimeInterval   ErrInvalidSubscription = errors.New(`Subscription timeInterval must be positive and can't be combined with "watchedAttributes" or "throttling"`)
+	ErrSubscriptionInvalidThrottling     ErrInvalidSubscription = errors.New(`Subscription throttling must be positive`)
+	ErrSubscriptionExpired               ErrInvalidSubscription = errors.New(`Subscription expiresAt must be in the future`)
+)
+
+type ErrInvalidTemporalEntity error
+
+var (
+	ErrTemporalEntityMixedTypes       ErrInvalidTemporalEntity = errors.New(`instances of a temporal Attribute must have the same type`)
+	ErrTemporalEntityInvalidPoint     ErrInvalidTemporalEntity = errors.New(`temporal values must be [value, time] pairs`)
+	ErrTemporalEntityInvalidAggregate ErrInvalidTemporalEntity = errors.New(`aggregated values must be [value, startAt, endAt] triples`)
+	ErrTemporalEntityDuplicateDataset ErrInvalidTemporalEntity = errors.New(`instances of a temporal Attribute must have different datasetIds`)
+	ErrTemporalValueWrongType         ErrInvalidTemporalEntity = errors.New(`temporal value has not the requested type`)
+)
+
+type ErrInvalidTemporalQuery error
+
+var (
+	ErrTemporalQueryInvalidTimerel  ErrInvalidTemporalQuery = errors.New(`TemporalQuery timerel must be "before", "after" or "between"`)
+	ErrTemporalQueryMissingTimeAt   ErrInvalidTemporalQuery = errors.New(`TemporalQuery must have a "timeAt" field`)
+	ErrTemporalQueryInvalidEndTime  ErrInvalidTemporalQuery = errors.New(`TemporalQuery endTimeAt must follow timeAt, and is allowed only with "between"`)
+	ErrTemporalQueryInvalidProperty ErrInvalidTemporalQuery = errors.New(`TemporalQuery timeproperty must be "observedAt", "createdAt" or "modifiedAt"`)
+)
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/pkg/errors"
+)
+
+// Expand returns a copy of the Entity whose type and Attribute names, sub-Attributes
+// included, are expanded to IRIs according to the active context
+func (e *Entity) Expand(activeCtx *ldcontext.ActiveContext) (*Entity, error) {
+	return e.mapTerms(activeCtx.ExpandTerm)
+}
+
+// Compact returns a copy of the Entity whose type and Attribute names, sub-Attributes
+// included, are compacted to the terms of the active context
+func (e *Entity) Compact(activeCtx *ldcontext.ActiveContext) (*Entity, error) {
+	return e.mapTerms(activeCtx.CompactIRI)
+}
+
+// termMapper turns a term into another one, e.g. by expanding it to IRI
+type termMapper func(string) string
+
+func (e *Entity) mapTerms(mapTerm termMapper) (*Entity, error) {
+	d := *e
+	d.Type = mapTerm(e.Type)
+
+	err := d.attributes().mapTerms(mapTerm)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attributes of different kinds must not end up with the same name
+	names := map[string]bool{}
+	for _, kind := range [][]string{
+		appendNames(nil, d.Properties),
+		appendNames(nil, d.Relationships),
+		appendNames(nil, d.LanguageProperties),
+		appendNames(nil, d.VocabProperties),
+		appendNames(nil, d.JSONProperties),
+		appendNames(nil, d.ListProperties),
+		appendNames(nil, d.ListRelationships),
+		appendNames(nil, d.GeoProperties),
+		d.geoPropertyFieldNames(),
+	} {
+		for _, name := range kind {
+			if names[name] {
+				return nil, errors.Wrapf(ErrEntityTermCollision, "term: %s", name)
+			}
+			names[name] = true
+		}
+	}
+
+	// The names of the GeoProperty fields are core terms, only their sub-Attributes change
+	for _, g := range []**GeoProperty{&d.Location, &d.ObservationSpace, &d.OperationSpace} {
+		if *g == nil {
+			continue
+		}
+		mapped := **g
+		err = mapped.members().subAttributes.mapTerms(mapTerm)
+		if err != nil {
+			return nil, err
+		}
+		*g = &mapped
+	}
+	return &d, nil
+}
+
+// geoPropertyFieldNames returns the names of the GeoProperty fields that are set
+func (e *Entity) geoPropertyFieldNames() []string {
+	names := []string{}
+	if e.Location != nil {
+		names = append(names, "location")
+	}
+	if e.ObservationSpace != nil {
+		names = append(names, "observationSpace")
+	}
+	if e.OperationSpace != nil {
+		names = append(names, "operationSpace")
+	}
+	return names
+}
+
+// mapAttributesTerms maps the names of the set of Attributes and of their sub-Attributes
+func mapAttributesTerms[M ~map[string]S, S ~[]T, T any, PT attributePointer[T]](attributes M, mapTerm termMapper) (M, error) {
+	if attributes == nil {
+		return nil, nil
+	}
+
+	mapped := M{}
+	for k, v := range attributes {
+		name := mapTerm(k)
+		if _, ok := mapped[name]; ok {
+			return nil, errors.Wrapf(ErrEntityTermCollision, "term: %s", name)
+		}
+
+		instances := make(S, 0, len(v))
+		for _, x := range v {
+			err := PT(&x).members().subAttributes.mapTerms(mapTerm)
+			if err != nil {
+				return nil, err
+			}
+			instances = append(instances, x)
+		}
+		mapped[name] = instances
+	}
+	return mapped, nil
+}
+
+// mapTerms replaces the sets with copies whose Attribute names are mapped
+func (s attributeSet) mapTerms(mapTerm termMapper) error {
+	var err error
+	if *s.properties, err = mapAttributesTerms(*s.properties, mapTerm); err != nil {
+		return err
+	}
+	if *s.relationships, err = mapAttributesTerms(*s.relationships, mapTerm); err != nil {
+		return err
+	}
+	if *s.geoProperties, err = mapAttributesTerms(*s.geoProperties, mapTerm); err != nil {
+		return err
+	}
+	if *s.languageProperties, err = mapAttributesTerms(*s.languageProperties, mapTerm); err != nil {
+		return err
+	}
+	if *s.vocabProperties, err = mapAttributesTerms(*s.vocabProperties, mapTerm); err != nil {
+		return err
+	}
+	if *s.jsonProperties, err = mapAttributesTerms(*s.jsonProperties, mapTerm); err != nil {
+		return err
+	}
+	if *s.listProperties, err = mapAttributesTerms(*s.listProperties, mapTerm); err != nil {
+		return err
+	}
+	*s.listRelationships, err = mapAttributesTerms(*s.listRelationships, mapTerm)
+	return err
+}
@@ -0,0 +1,81 @@
+package model_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phoops/ngsi-gold/ldcontext"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandCompactEntity(t *testing.T) {
+	activeCtx, err := ldcontext.Resolve(context.Background(), ldcontext.LdContext{
+		map[string]any{
+			"Room":        "https://example.org/Room",
+			"temperature": "https://example.org/temperature",
+			"accuracy":    "https://example.org/accuracy",
+			"wall":        "https://example.org/wall",
+		},
+	}, nil)
+	assert.NoError(t, err)
+
+	compacted := model.Entity{
+		ID:   "urn:room:1",
+		Type: "Room",
+		Properties: model.Properties{
+			"temperature": {{
+				Value: 21.5,
+				Properties: model.Properties{
+					"accuracy": {{Value: 0.1}},
+				},
+			}},
+			"humidity": {{Value: 40}},
+		},
+		Relationships: model.Relationships{
+			"wall": {{Object: "urn:wall:1"}},
+		},
+	}
+	expanded := model.Entity{
+		ID:   "urn:room:1",
+		Type: "https://example.org/Room",
+		Properties: model.Properties{
+			"https://example.org/temperature": {{
+				Value: 21.5,
+				Properties: model.Properties{
+					"https://example.org/accuracy": {{Value: 0.1}},
+				},
+			}},
+			"https://uri.etsi.org/ngsi-ld/default-context/humidity": {{Value: 40}},
+		},
+		Relationships: model.Relationships{
+			"https://example.org/wall": {{Object: "urn:wall:1"}},
+		},
+	}
+
+	e, err := compacted.Expand(activeCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, &expanded, e)
+
+	e, err = expanded.Compact(activeCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, &compacted, e)
+}
+
+func TestExpandEntityCollision(t *testing.T) {
+	activeCtx, err := ldcontext.Resolve(context.Background(), ldcontext.LdContext{
+		map[string]any{"temperature": "https://example.org/temperature"},
+	}, nil)
+	assert.NoError(t, err)
+
+	e := model.Entity{
+		ID:   "urn:room:1",
+		Type: "Room",
+		Properties: model.Properties{
+			"temperature":                     {{Value: 21.5}},
+			"https://example.org/temperature": {{Value: 22}},
+		},
+	}
+	_, err = e.Expand(activeCtx)
+	assert.ErrorIs(t, err, model.ErrEntityTermCollision)
+}
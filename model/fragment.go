@@ -0,0 +1,111 @@
+package model
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// EntityFragment is a set of Attributes of an Entity, without its ID and type.
+// It is the payload of the operations updating or appending Attributes.
+type EntityFragment struct {
+	Properties         Properties
+	Relationships      Relationships
+	GeoProperties      GeoProperties
+	LanguageProperties LanguageProperties
+	VocabProperties    VocabProperties
+	JSONProperties     JSONProperties
+	ListProperties     ListProperties
+	ListRelationships  ListRelationships
+}
+
+func (f EntityFragment) MarshalJSON() ([]byte, error) {
+	data := map[string]any{}
+
+	for k, v := range f.Properties {
+		data[k] = v
+	}
+
+	for k, v := range f.Relationships {
+		data[k] = v
+	}
+
+	for k, v := range f.GeoProperties {
+		data[k] = v
+	}
+
+	marshalAttributes(data, f.LanguageProperties)
+	marshalAttributes(data, f.VocabProperties)
+	marshalAttributes(data, f.JSONProperties)
+	marshalAttributes(data, f.ListProperties)
+	marshalAttributes(data, f.ListRelationships)
+
+	return json.Marshal(data)
+}
+
+// Names returns the sorted names of the Attributes in the fragment
+func (f *EntityFragment) Names() []string {
+	names := []string{}
+	for k := range f.Properties {
+		names = append(names, k)
+	}
+	for k := range f.Relationships {
+		names = append(names, k)
+	}
+	for k := range f.GeoProperties {
+		names = append(names, k)
+	}
+	names = appendNames(names, f.LanguageProperties)
+	names = appendNames(names, f.VocabProperties)
+	names = appendNames(names, f.JSONProperties)
+	names = appendNames(names, f.ListProperties)
+	names = appendNames(names, f.ListRelationships)
+	sort.Strings(names)
+	return names
+}
+
+func (f *EntityFragment) Validate(strictness bool) ValidationResult {
+	if len(f.Properties)+len(f.Relationships)+len(f.GeoProperties)+
+		len(f.LanguageProperties)+len(f.VocabProperties)+len(f.JSONProperties)+
+		len(f.ListProperties)+len(f.ListRelationships) == 0 {
+		return ErrEntityFragmentEmpty
+	}
+
+	for _, x := range f.Properties {
+		err := x.Validate(strictness)
+		if err != nil {
+			return err
+		}
+	}
+	for _, x := range f.Relationships {
+		err := x.Validate(strictness)
+		if err != nil {
+			return err
+		}
+	}
+	for _, x := range f.GeoProperties {
+		err := x.Validate(strictness)
+		if err != nil {
+			return err
+		}
+	}
+	for _, err := range []ValidationResult{
+		validateAttributes(f.LanguageProperties, strictness),
+		validateAttributes(f.VocabProperties, strictness),
+		validateAttributes(f.JSONProperties, strictness),
+		validateAttributes(f.ListProperties, strictness),
+		validateAttributes(f.ListRelationships, strictness),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendNames appends the names of the set of Attributes
+func appendNames[M ~map[string]S, S any](names []string, attributes M) []string {
+	for k := range attributes {
+		names = append(names, k)
+	}
+	return names
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/philiphil/geojson"
+)
+
+var georelPattern = regexp.MustCompile(`^(within|contains|intersects|equals|disjoint|overlaps|near;(maxDistance|minDistance)==[0-9]+(\.[0-9]+)?)$`)
+
+// GeoQuery selects entities by the spatial relationship between one of their
+// GeoProperties and a reference geometry
+type GeoQuery struct {
+	Geometry    string `json:"geometry"`              // GeoJSON type of the reference geometry
+	Coordinates any    `json:"coordinates"`           // GeoJSON coordinates of the reference geometry
+	Georel      string `json:"georel"`                // Relationship to check, e.g. "within" or "near;maxDistance==100"
+	GeoProperty string `json:"geoproperty,omitempty"` // GeoProperty to check, location when missing
+}
+
+// NewGeoQuery builds a GeoQuery having the GeoJSON geometry as reference
+func NewGeoQuery(georel string, geometry *geojson.Geometry) (*GeoQuery, error) {
+	if geometry == nil {
+		return nil, ErrGeoQueryMissingGeometry
+	}
+	if !validGeoPropertyValue(geometry) {
+		return nil, ErrGeoQueryInvalidGeometry
+	}
+
+	// Let the GeoJSON library pick the coordinates matching the geometry type
+	serialized, err := json.Marshal(geometry)
+	if err != nil {
+		return nil, err
+	}
+	coordinates := struct {
+		Coordinates any `json:"coordinates"`
+	}{}
+	if err := json.Unmarshal(serialized, &coordinates); err != nil {
+		return nil, err
+	}
+
+	q := &GeoQuery{
+		Geometry:    string(geometry.Type),
+		Coordinates: coordinates.Coordinates,
+		Georel:      georel,
+	}
+	if err := q.Validate(true); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *GeoQuery) Validate(strictness bool) ValidationResult {
+	if q.Geometry == "" {
+		return ErrGeoQueryMissingGeometry
+	}
+	switch geojson.GeometryType(q.Geometry) {
+	case geojson.GeometryPoint,
+		geojson.GeometryMultiPoint,
+		geojson.GeometryLineString,
+		geojson.GeometryMultiLineString,
+		geojson.GeometryPolygon,
+		geojson.GeometryMultiPolygon:
+	default:
+		return ErrGeoQueryInvalidGeometry
+	}
+	if q.Coordinates == nil {
+		return ErrGeoQueryMissingCoordinates
+	}
+	if !georelPattern.MatchString(q.Georel) {
+		return ErrGeoQueryInvalidGeorel
+	}
+	return nil
+}
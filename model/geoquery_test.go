@@ -0,0 +1,58 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/philiphil/geojson"
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGeoQuery(t *testing.T) {
+	q, err := model.NewGeoQuery("near;maxDistance==2000", geojson.NewPointGeometry([]float64{11.25, 43.77}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Point", q.Geometry)
+	assert.Equal(t, []any{11.25, 43.77}, q.Coordinates)
+}
+
+func TestGeoQueryValidation(t *testing.T) {
+	type testCase struct {
+		name     string
+		geoQuery model.GeoQuery
+		err      error
+	}
+
+	tests := []testCase{
+		{
+			name:     "missing geometry",
+			geoQuery: model.GeoQuery{Coordinates: []float64{1, 2}, Georel: "within"},
+			err:      model.ErrGeoQueryMissingGeometry,
+		},
+		{
+			name:     "geometry collection",
+			geoQuery: model.GeoQuery{Geometry: "GeometryCollection", Coordinates: []float64{1, 2}, Georel: "within"},
+			err:      model.ErrGeoQueryInvalidGeometry,
+		},
+		{
+			name:     "missing coordinates",
+			geoQuery: model.GeoQuery{Geometry: "Point", Georel: "within"},
+			err:      model.ErrGeoQueryMissingCoordinates,
+		},
+		{
+			name:     "unknown georel",
+			geoQuery: model.GeoQuery{Geometry: "Point", Coordinates: []float64{1, 2}, Georel: "around"},
+			err:      model.ErrGeoQueryInvalidGeorel,
+		},
+		{
+			name:     "near without distance",
+			geoQuery: model.GeoQuery{Geometry: "Point", Coordinates: []float64{1, 2}, Georel: "near"},
+			err:      model.ErrGeoQueryInvalidGeorel,
+		},
+	}
+
+	for _, y := range tests {
+		t.Run(y.name, func(t *testing.T) {
+			assert.ErrorIs(t, y.geoQuery.Validate(true), y.err)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Instances are the instances of an Attribute, at most one for each datasetId.
+// The default instance has no datasetId.
+type Instances[T instance] []T
+
+// AttributeMap defines a set of Attributes of the same kind, identified by a string.
+// Each name holds the instances of the Attribute, one for each datasetId.
+type AttributeMap[T instance] map[string]Instances[T]
+
+// PropertyInstances are the instances of a Property
+type PropertyInstances = Instances[Property]
+
+// RelationshipInstances are the instances of a Relationship
+type RelationshipInstances = Instances[Relationship]
+
+// GeoPropertyInstances are the instances of a GeoProperty
+type GeoPropertyInstances = Instances[GeoProperty]
+
+// LanguagePropertyInstances are the instances of a LanguageProperty
+type LanguagePropertyInstances = Instances[LanguageProperty]
+
+// VocabPropertyInstances are the instances of a VocabProperty
+type VocabPropertyInstances = Instances[VocabProperty]
+
+// JSONPropertyInstances are the instances of a JsonProperty
+type JSONPropertyInstances = Instances[JSONProperty]
+
+// ListPropertyInstances are the instances of a ListProperty
+type ListPropertyInstances = Instances[ListProperty]
+
+// ListRelationshipInstances are the instances of a ListRelationship
+type ListRelationshipInstances = Instances[ListRelationship]
+
+// instance is implemented by every kind of Attribute, to manage their instances
+type instance interface {
+	dataset() *string
+}
+
+func (p Property) dataset() *string         { return p.DatasetID }
+func (r Relationship) dataset() *string     { return r.DatasetID }
+func (p GeoProperty) dataset() *string      { return p.DatasetID }
+func (p LanguageProperty) dataset() *string { return p.DatasetID }
+func (p VocabProperty) dataset() *string    { return p.DatasetID }
+func (p JSONProperty) dataset() *string     { return p.DatasetID }
+func (p ListProperty) dataset() *string     { return p.DatasetID }
+func (r ListRelationship) dataset() *string { return r.DatasetID }
+
+// Set adds the Attribute to the instances named name,
+// replacing the instance having the same datasetId
+func (m AttributeMap[T]) Set(name string, attribute T) {
+	m[name] = setInstance(m[name], attribute)
+}
+
+// Default returns the instance without datasetId
+func (i Instances[T]) Default() (T, bool) {
+	return byDataset(i, "")
+}
+
+// ByDataset returns the instance having the datasetId, the default one when empty
+func (i Instances[T]) ByDataset(datasetID string) (T, bool) {
+	return byDataset(i, datasetID)
+}
+
+// MarshalJSON serializes a single instance as an object, several ones as an array
+func (i Instances[T]) MarshalJSON() ([]byte, error) {
+	if len(i) == 1 {
+		return json.Marshal(i[0])
+	}
+	return json.Marshal([]T(i))
+}
+
+// UnmarshalJSON decodes an instance, or an array of instances
+func (i *Instances[T]) UnmarshalJSON(b []byte) error {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		d := []T{}
+		err := json.Unmarshal(b, &d)
+		if err != nil {
+			return err
+		}
+		*i = d
+		return nil
+	}
+
+	var d T
+	err := json.Unmarshal(b, &d)
+	if err != nil {
+		return err
+	}
+	*i = Instances[T]{d}
+	return nil
+}
+
+// Validate checks every instance, and that their datasetIds differ
+func (i Instances[T]) Validate(strictness bool) ValidationResult {
+	if len(i) == 0 {
+		return ErrAttributeMissingInstances
+	}
+	seen := map[string]bool{}
+	for k := range i {
+		// Every kind of Attribute is validated through its pointer
+		err := any(&i[k]).(Validatable).Validate(strictness)
+		if err != nil {
+			return err
+		}
+
+		key := ""
+		if id := i[k].dataset(); id != nil {
+			key = *id
+		}
+		if seen[key] {
+			if key == "" {
+				return errors.Wrap(ErrAttributeDuplicateDataset, "default instance")
+			}
+			return errors.Wrapf(ErrAttributeDuplicateDataset, "datasetId %s", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func setInstance[S ~[]T, T instance](instances S, x T) S {
+	for i := range instances {
+		if sameDataset(instances[i].dataset(), x.dataset()) {
+			instances[i] = x
+			return instances
+		}
+	}
+	return append(instances, x)
+}
+
+func byDataset[S ~[]T, T instance](instances S, datasetID string) (T, bool) {
+	for _, x := range instances {
+		if sameDataset(x.dataset(), &datasetID) {
+			return x, true
+		}
+	}
+	var missing T
+	return missing, false
+}
+
+// sameDataset tells if the datasetIds are the same, nil and empty are the default dataset
+func sameDataset(a, b *string) bool {
+	return (a == nil || *a == "") && (b == nil || *b == "") ||
+		a != nil && b != nil && *a == *b
+}
+
+// attributeType returns the type of the instances of an Attribute,
+// given as a single object or as an array of objects
+func attributeType(b json.RawMessage) (string, error) {
+	type Attribute struct {
+		Type string `json:"type,omitempty"`
+	}
+
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		a := Attribute{}
+		err := json.Unmarshal(b, &a)
+		return a.Type, err
+	}
+
+	instances := []Attribute{}
+	err := json.Unmarshal(b, &instances)
+	if err != nil {
+		return "", err
+	}
+	typ := ""
+	for i, a := range instances {
+		if i > 0 && a.Type != typ {
+			return "", ErrAttributeMixedInstanceTypes
+		}
+		typ = a.Type
+	}
+	return typ, nil
+}
@@ -0,0 +1,129 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalMultiAttribute(t *testing.T) {
+	e := model.Entity{}
+	err := json.Unmarshal([]byte(`{
+		"id": "urn:ngsi-ld:Room:1",
+		"type": "Room",
+		"temperature": [
+			{"type": "Property", "value": 21.5},
+			{"type": "Property", "value": 21.7, "datasetId": "urn:ngsi-ld:Dataset:providerA"},
+			{"type": "Property", "value": 21.2, "datasetId": "urn:ngsi-ld:Dataset:providerB"}
+		],
+		"wall": {"type": "Relationship", "object": "urn:wall:1"}
+	}`), &e)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Validate(true))
+
+	temperature := e.Properties["temperature"]
+	assert.Len(t, temperature, 3)
+	p, ok := temperature.Default()
+	assert.True(t, ok)
+	assert.Equal(t, 21.5, p.Value)
+	p, ok = temperature.ByDataset("urn:ngsi-ld:Dataset:providerB")
+	assert.True(t, ok)
+	assert.Equal(t, 21.2, p.Value)
+	_, ok = temperature.ByDataset("urn:ngsi-ld:Dataset:providerC")
+	assert.False(t, ok)
+
+	// A single instance is an object
+	r, ok := e.Relationships["wall"].Default()
+	assert.True(t, ok)
+	assert.Equal(t, "urn:wall:1", r.Object)
+
+	b, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": "urn:ngsi-ld:Room:1",
+		"type": "Room",
+		"temperature": [
+			{"type": "Property", "value": 21.5},
+			{"type": "Property", "value": 21.7, "datasetId": "urn:ngsi-ld:Dataset:providerA"},
+			{"type": "Property", "value": 21.2, "datasetId": "urn:ngsi-ld:Dataset:providerB"}
+		],
+		"wall": {"type": "Relationship", "object": "urn:wall:1"}
+	}`, string(b))
+}
+
+func TestUnmarshalMultiAttributeMixedTypes(t *testing.T) {
+	e := model.Entity{}
+	err := json.Unmarshal([]byte(`{
+		"id": "urn:ngsi-ld:Room:1",
+		"type": "Room",
+		"temperature": [
+			{"type": "Property", "value": 21.5},
+			{"type": "Relationship", "object": "urn:sensor:1", "datasetId": "urn:ngsi-ld:Dataset:providerA"}
+		]
+	}`), &e)
+	assert.ErrorIs(t, err, model.ErrAttributeMixedInstanceTypes)
+}
+
+func TestSetMultiAttribute(t *testing.T) {
+	providerA := "urn:ngsi-ld:Dataset:providerA"
+	properties := model.Properties{}
+	properties.Set("temperature", model.Property{Value: 21.5})
+	properties.Set("temperature", model.Property{Value: 21.7, DatasetID: &providerA})
+	properties.Set("temperature", model.Property{Value: 22.0})
+	assert.Equal(t, model.PropertyInstances{
+		{Value: 22.0},
+		{Value: 21.7, DatasetID: &providerA},
+	}, properties["temperature"])
+
+	e := model.Entity{
+		ID:   "urn:ngsi-ld:Room:1",
+		Type: "Room",
+		Properties: model.Properties{"temperature": {
+			{Value: 21.5, DatasetID: &providerA},
+			{Value: 21.7, DatasetID: &providerA},
+		}},
+	}
+	assert.ErrorIs(t, e.Validate(true), model.ErrAttributeDuplicateDataset)
+
+	e.Properties["temperature"] = model.PropertyInstances{}
+	assert.ErrorIs(t, e.Validate(true), model.ErrAttributeMissingInstances)
+}
+
+func TestMarshalEntityStructInstances(t *testing.T) {
+	type reading struct {
+		Value     float64 `ngsi:",value"`
+		DatasetID string  `ngsi:",datasetId"`
+	}
+	type room struct {
+		ID          string    `ngsi:",id"`
+		Type        string    `ngsi:"Room,type"`
+		Temperature []reading `ngsi:"temperature,property"`
+		Humidity    reading   `ngsi:"humidity,property"`
+	}
+
+	providerA := "urn:ngsi-ld:Dataset:providerA"
+	r := room{
+		ID:          "urn:ngsi-ld:Room:1",
+		Temperature: []reading{{Value: 21.5}, {Value: 21.7, DatasetID: providerA}},
+		Humidity:    reading{Value: 40, DatasetID: providerA},
+	}
+	e, err := model.MarshalEntity(r)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Properties{
+		"temperature": {{Value: 21.5}, {Value: 21.7, DatasetID: &providerA}},
+		"humidity":    {{Value: 40.0, DatasetID: &providerA}},
+	}, e.Properties)
+
+	decoded := room{}
+	assert.NoError(t, model.UnmarshalEntity(e, &decoded))
+	r.Type = "Room"
+	assert.Equal(t, r, decoded)
+
+	// Several instances don't fit a single field
+	providerB := "urn:ngsi-ld:Dataset:providerB"
+	e.Properties.Set("humidity", model.Property{Value: 42.0, DatasetID: &providerB})
+	err = model.UnmarshalEntity(e, &decoded)
+	assert.ErrorIs(t, err, model.ErrCodecWrongType)
+}
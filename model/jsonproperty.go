@@ -0,0 +1,94 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// JSONProperty is an Attribute that holds a JSON value as is,
+// its members are not interpreted as JSON-LD terms
+type JSONProperty struct {
+	JSON               any                `json:"json"`
+	Properties         Properties         `json:"-"`
+	Relationships      Relationships      `json:"-"`
+	GeoProperties      GeoProperties      `json:"-"`
+	LanguageProperties LanguageProperties `json:"-"`
+	VocabProperties    VocabProperties    `json:"-"`
+	JSONProperties     JSONProperties     `json:"-"`
+	ListProperties     ListProperties     `json:"-"`
+	ListRelationships  ListRelationships  `json:"-"`
+	ObservedAt         *time.Time         `json:"observedAt,omitempty"`
+	DatasetID          *string            `json:"datasetId,omitempty"`
+	InstanceID         *string            `json:"instanceId,omitempty"`
+	CreatedAt          *time.Time         `json:"createdAt,omitempty"`
+	ModifiedAt         *time.Time         `json:"modifiedAt,omitempty"`
+	DeletedAt          *time.Time         `json:"deletedAt,omitempty"`
+}
+
+func (p *JSONProperty) Type() string {
+	return "JsonProperty"
+}
+
+func (p JSONProperty) MarshalJSON() ([]byte, error) {
+	data := map[string]any{}
+
+	data["type"] = p.Type()
+	data["json"] = p.JSON
+	p.members().marshal(data)
+
+	return json.Marshal(data)
+}
+
+func (p *JSONProperty) UnmarshalJSON(b []byte) error {
+	// Use an alias to avoid recursion into this function
+	type readJSONProperty JSONProperty
+
+	d := readJSONProperty{}
+	if err := json.Unmarshal(b, &d); err != nil {
+		return ErrInvalidJSONProperty(err)
+	}
+
+	// Check for missing mandatory value
+	if d.JSON == nil {
+		return ErrJSONPropertyMissingJSON
+	}
+
+	err := unmarshalSubAttributes(b, "JsonProperty", ErrJSONPropertyWrongType, reflect.TypeOf(d), (*JSONProperty)(&d).members().subAttributes)
+	if err != nil {
+		return ErrInvalidJSONProperty(err)
+	}
+
+	// Assign fields to pointed structure
+	*p = JSONProperty(d)
+
+	return nil
+}
+
+func (p *JSONProperty) Validate(strictness bool) ValidationResult {
+	if p.JSON == nil {
+		return ErrJSONPropertyMissingJSON
+	}
+	return p.members().validate(strictness)
+}
+
+func (p *JSONProperty) members() attributeMembers {
+	return attributeMembers{
+		observedAt: p.ObservedAt,
+		datasetID:  p.DatasetID,
+		instanceID: p.InstanceID,
+		createdAt:  p.CreatedAt,
+		modifiedAt: p.ModifiedAt,
+		deletedAt:  p.DeletedAt,
+		subAttributes: attributeSet{
+			properties:         &p.Properties,
+			relationships:      &p.Relationships,
+			geoProperties:      &p.GeoProperties,
+			languageProperties: &p.LanguageProperties,
+			vocabProperties:    &p.VocabProperties,
+			jsonProperties:     &p.JSONProperties,
+			listProperties:     &p.ListProperties,
+			listRelationships:  &p.ListRelationships,
+		},
+	}
+}
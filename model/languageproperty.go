@@ -0,0 +1,94 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// LanguageProperty is an Attribute that holds a string in several languages,
+// identified by their language tag (e.g. "en")
+type LanguageProperty struct {
+	LanguageMap        map[string]string  `json:"languageMap"`
+	Properties         Properties         `json:"-"`
+	Relationships      Relationships      `json:"-"`
+	GeoProperties      GeoProperties      `json:"-"`
+	LanguageProperties LanguageProperties `json:"-"`
+	VocabProperties    VocabProperties    `json:"-"`
+	JSONProperties     JSONProperties     `json:"-"`
+	ListProperties     ListProperties     `json:"-"`
+	ListRelationships  ListRelationships  `json:"-"`
+	ObservedAt         *time.Time         `json:"observedAt,omitempty"`
+	DatasetID          *string            `json:"datasetId,omitempty"`
+	InstanceID         *string            `json:"instanceId,omitempty"`
+	CreatedAt          *time.Time         `json:"createdAt,omitempty"`
+	ModifiedAt         *time.Time         `json:"modifiedAt,omitempty"`
+	DeletedAt          *time.Time         `json:"deletedAt,omitempty"`
+}
+
+func (p *LanguageProperty) Type() string {
+	return "LanguageProperty"
+}
+
+func (p LanguageProperty) MarshalJSON() ([]byte, error) {
+	data := map[string]any{}
+
+	data["type"] = p.Type()
+	data["languageMap"] = p.LanguageMap
+	p.members().marshal(data)
+
+	return json.Marshal(data)
+}
+
+func (p *LanguageProperty) UnmarshalJSON(b []byte) error {
+	// Use an alias to avoid recursion into this function
+	type readLanguageProperty LanguageProperty
+
+	d := readLanguageProperty{}
+	if err := json.Unmarshal(b, &d); err != nil {
+		return ErrInvalidLanguageProperty(err)
+	}
+
+	// Check for missing mandatory value
+	if d.LanguageMap == nil {
+		return ErrLanguagePropertyMissingMap
+	}
+
+	err := unmarshalSubAttributes(b, "LanguageProperty", ErrLanguagePropertyWrongType, reflect.TypeOf(d), (*LanguageProperty)(&d).members().subAttributes)
+	if err != nil {
+		return ErrInvalidLanguageProperty(err)
+	}
+
+	// Assign fields to pointed structure
+	*p = LanguageProperty(d)
+
+	return nil
+}
+
+func (p *LanguageProperty) Validate(strictness bool) ValidationResult {
+	if p.LanguageMap == nil {
+		return ErrLanguagePropertyMissingMap
+	}
+	return p.members().validate(strictness)
+}
+
+func (p *LanguageProperty) members() attributeMembers {
+	return attributeMembers{
+		observedAt: p.ObservedAt,
+		datasetID:  p.DatasetID,
+		instanceID: p.InstanceID,
+		createdAt:  p.CreatedAt,
+		modifiedAt: p.ModifiedAt,
+		deletedAt:  p.DeletedAt,
+		subAttributes: attributeSet{
+			properties:         &p.Properties,
+			relationships:      &p.Relationships,
+			geoProperties:      &p.GeoProperties,
+			languageProperties: &p.LanguageProperties,
+			vocabProperties:    &p.VocabProperties,
+			jsonProperties:     &p.JSONProperties,
+			listProperties:     &p.ListProperties,
+			listRelationships:  &p.ListRelationships,
+		},
+	}
+}
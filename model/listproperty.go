@@ -0,0 +1,97 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// ListProperty is an Attribute that holds an ordered list of values
+type ListProperty struct {
+	ValueList          []any              `json:"valueList"`
+	Properties         Properties         `json:"-"`
+	Relationships      Relationships      `json:"-"`
+	GeoProperties      GeoProperties      `json:"-"`
+	LanguageProperties LanguageProperties `json:"-"`
+	VocabProperties    VocabProperties    `json:"-"`
+	JSONProperties     JSONProperties     `json:"-"`
+	ListProperties     ListProperties     `json:"-"`
+	ListRelationships  ListRelationships  `json:"-"`
+	ObservedAt         *time.Time         `json:"observedAt,omitempty"`
+	UnitCode           *string            `json:"unitCode,omitempty"`
+	DatasetID          *string            `json:"datasetId,omitempty"`
+	InstanceID         *string            `json:"instanceId,omitempty"`
+	CreatedAt          *time.Time         `json:"createdAt,omitempty"`
+	ModifiedAt         *time.Time         `json:"modifiedAt,omitempty"`
+	DeletedAt          *time.Time         `json:"deletedAt,omitempty"`
+}
+
+func (p *ListProperty) Type() string {
+	return "ListProperty"
+}
+
+func (p ListProperty) MarshalJSON() ([]byte, error) {
+	data := map[string]any{}
+
+	data["type"] = p.Type()
+	data["valueList"] = p.ValueList
+	if p.UnitCode != nil {
+		data["unitCode"] = p.UnitCode
+	}
+	p.members().marshal(data)
+
+	return json.Marshal(data)
+}
+
+func (p *ListProperty) UnmarshalJSON(b []byte) error {
+	// Use an alias to avoid recursion into this function
+	type readListProperty ListProperty
+
+	d := readListProperty{}
+	if err := json.Unmarshal(b, &d); err != nil {
+		return ErrInvalidListProperty(err)
+	}
+
+	// Check for missing mandatory value
+	if d.ValueList == nil {
+		return ErrListPropertyMissingValueList
+	}
+
+	err := unmarshalSubAttributes(b, "ListProperty", ErrListPropertyWrongType, reflect.TypeOf(d), (*ListProperty)(&d).members().subAttributes)
+	if err != nil {
+		return ErrInvalidListProperty(err)
+	}
+
+	// Assign fields to pointed structure
+	*p = ListProperty(d)
+
+	return nil
+}
+
+func (p *ListProperty) Validate(strictness bool) ValidationResult {
+	if p.ValueList == nil {
+		return ErrListPropertyMissingValueList
+	}
+	return p.members().validate(strictness)
+}
+
+func (p *ListProperty) members() attributeMembers {
+	return attributeMembers{
+		observedAt: p.ObservedAt,
+		datasetID:  p.DatasetID,
+		instanceID: p.InstanceID,
+		createdAt:  p.CreatedAt,
+		modifiedAt: p.ModifiedAt,
+		deletedAt:  p.DeletedAt,
+		subAttributes: attributeSet{
+			properties:         &p.Properties,
+			relationships:      &p.Relationships,
+			geoProperties:      &p.GeoProperties,
+			languageProperties: &p.LanguageProperties,
+			vocabProperties:    &p.VocabProperties,
+			jsonProperties:     &p.JSONProperties,
+			listProperties:     &p.ListProperties,
+			listRelationships:  &p.ListRelationships,
+		},
+	}
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// ListRelationship is an Attribute that links to an ordered list of entities
+type ListRelationship struct {
+	ObjectList         []string           `json:"-"`
+	Properties         Properties         `json:"-"`
+	Relationships      Relationships      `json:"-"`
+	GeoProperties      GeoProperties      `json:"-"`
+	LanguageProperties LanguageProperties `json:"-"`
+	VocabProperties    VocabProperties    `json:"-"`
+	JSONProperties     JSONProperties     `json:"-"`
+	ListProperties     ListProperties     `json:"-"`
+	ListRelationships  ListRelationships  `json:"-"`
+	ObservedAt         *time.Time         `json:"observedAt,omitempty"`
+	DatasetID          *string            `json:"datasetId,omitempty"`
+	InstanceID         *string            `json:"instanceId,omitempty"`
+	CreatedAt          *time.Time         `json:"createdAt,omitempty"`
+	ModifiedAt         *time.Time         `json:"modifiedAt,omitempty"`
+	DeletedAt          *time.Time         `json:"deletedAt,omitempty"`
+}
+
+// listObject is an item of the objectList of a ListRelationship
+type listObject struct {
+	Object string `json:"object"`
+}
+
+func (r *ListRelationship) Type() string {
+	return "ListRelationship"
+}
+
+func (r ListRelationship) MarshalJSON() ([]byte, error) {
+	data := map[string]any{}
+
+	data["type"] = r.Type()
+	objects := make([]listObject, 0, len(r.ObjectList))
+	for _, object := range r.ObjectList {
+		objects = append(objects, listObject{Object: object})
+	}
+	data["objectList"] = objects
+	r.members().marshal(data)
+
+	return json.Marshal(data)
+}
+
+func (r *ListRelationship) UnmarshalJSON(b []byte) error {
+	// Use an alias to avoid recursion into this function
+	type readListRelationship ListRelationship
+
+	d := readListRelationship{}
+	if err := json.Unmarshal(b, &d); err != nil {
+		return ErrInvalidListRelationship(err)
+	}
+
+	// Items are objects, or bare URIs in the concise representation
+	objectList := struct {
+		ObjectList []json.RawMessage `json:"objectList"`
+	}{}
+	if err := json.Unmarshal(b, &objectList); err != nil {
+		return ErrListRelationshipInvalidObjectList
+	}
+	if objectList.ObjectList == nil {
+		return ErrListRelationshipMissingObjectList
+	}
+	d.ObjectList = make([]string, 0, len(objectList.ObjectList))
+	for _, item := range objectList.ObjectList {
+		object := listObject{}
+		if err := json.Unmarshal(item, &object); err != nil {
+			if err := json.Unmarshal(item, &object.Object); err != nil {
+				return ErrListRelationshipInvalidObjectList
+			}
+		}
+		d.ObjectList = append(d.ObjectList, object.Object)
+	}
+
+	err := unmarshalSubAttributes(b, "ListRelationship", ErrListRelationshipWrongType, reflect.TypeOf(d), (*ListRelationship)(&d).members().subAttributes, "objectList")
+	if err != nil {
+		return ErrInvalidListRelationship(err)
+	}
+
+	// Assign fields to pointed structure
+	*r = ListRelationship(d)
+
+	return nil
+}
+
+func (r *ListRelationship) Validate(strictness bool) ValidationResult {
+	if r.ObjectList == nil {
+		return ErrListRelationshipMissingObjectList
+	}
+	for _, object := range r.ObjectList {
+		if object == "" {
+			return ErrListRelationshipInvalidObjectList
+		}
+	}
+	return r.members().validate(strictness)
+}
+
+func (r *ListRelationship) members() attributeMembers {
+	return attributeMembers{
+		observedAt: r.ObservedAt,
+		datasetID:  r.DatasetID,
+		instanceID: r.InstanceID,
+		createdAt:  r.CreatedAt,
+		modifiedAt: r.ModifiedAt,
+		deletedAt:  r.DeletedAt,
+		subAttributes: attributeSet{
+			properties:         &r.Properties,
+			relationships:      &r.Relationships,
+			geoProperties:      &r.GeoProperties,
+			languageProperties: &r.LanguageProperties,
+			vocabProperties:    &r.VocabProperties,
+			jsonProperties:     &r.JSONProperties,
+			listProperties:     &r.ListProperties,
+			listRelationships:  &r.ListRelationships,
+		},
+	}
+}
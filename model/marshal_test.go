@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/philiphil/geojson"
 	"github.com/phoops/ngsi-gold/model"
 	"github.com/stretchr/testify/assert"
 )
@@ -65,7 +66,7 @@ func TestMarshalEntity(t *testing.T) {
 				ID:   "room:2",
 				Type: "Room",
 				Properties: model.Properties{
-					"key": model.Property{Value: true},
+					"key": {{Value: true}},
 				},
 			},
 			json: `{"id":"room:2","key":{"type":"Property","value":true},"type":"Room"}`,
@@ -76,7 +77,7 @@ func TestMarshalEntity(t *testing.T) {
 				ID:   "bulb:3",
 				Type: "Light",
 				Relationships: model.Relationships{
-					"wall": model.Relationship{Object: "wall:north-east"},
+					"wall": {{Object: "wall:north-east"}},
 				},
 			},
 			json: `{"id":"bulb:3","type":"Light","wall":{"object":"wall:north-east","type":"Relationship"}}`,
@@ -87,12 +88,12 @@ func TestMarshalEntity(t *testing.T) {
 				ID:   "cabin:4",
 				Type: "thing",
 				Properties: model.Properties{
-					"light": model.Property{
+					"light": {{
 						Value: 100,
 						Relationships: model.Relationships{
-							"wall": model.Relationship{Object: "wall:right"},
+							"wall": {{Object: "wall:right"}},
 						},
-					},
+					}},
 				},
 			},
 			json: `{"id":"cabin:4","light":{"type":"Property","value":100,"wall":{"object":"wall:right","type":"Relationship"}},"type":"thing"}`,
@@ -103,26 +104,26 @@ func TestMarshalEntity(t *testing.T) {
 				ID:   "cabin:4",
 				Type: "thing",
 				Properties: model.Properties{
-					"light": model.Property{
+					"light": {{
 						Value: 100,
 						Relationships: model.Relationships{
-							"wall": model.Relationship{Object: "wall:right"},
+							"wall": {{Object: "wall:right"}},
 						},
 						Properties: model.Properties{
-							"color": model.Property{Value: "white"},
+							"color": {{Value: "white"}},
 						},
-					},
+					}},
 				},
 				Relationships: model.Relationships{
-					"town": model.Relationship{
+					"town": {{
 						Object: "town:rome",
 						Relationships: model.Relationships{
-							"neighborhood": model.Relationship{Object: "town:rome:neighborhood:eur"},
+							"neighborhood": {{Object: "town:rome:neighborhood:eur"}},
 						},
 						Properties: model.Properties{
-							"transient": model.Property{Value: false},
+							"transient": {{Value: false}},
 						},
-					},
+					}},
 				},
 			},
 			json: `{"id":"cabin:4","light":{"color":{"type":"Property","value":"white"},"type":"Property","value":100,"wall":{"object":"wall:right","type":"Relationship"}},"town":{"neighborhood":{"object":"town:rome:neighborhood:eur","type":"Relationship"},"object":"town:rome","transient":{"type":"Property","value":false},"type":"Relationship"},"type":"thing"}`,
@@ -133,10 +134,10 @@ func TestMarshalEntity(t *testing.T) {
 				ID:   "cabin:4",
 				Type: "thing",
 				Relationships: model.Relationships{
-					"town": model.Relationship{
+					"town": {{
 						Object:     "town:rome",
 						ObservedAt: &observedTest,
-					},
+					}},
 				},
 			},
 			json: fmt.Sprintf(`{"id":"cabin:4","town":{"object":"town:rome","observedAt":"%s","type":"Relationship"},"type":"thing"}`, "2023-02-13T11:30:40.123456Z"),
@@ -147,10 +148,10 @@ func TestMarshalEntity(t *testing.T) {
 				ID:   "cabin:4",
 				Type: "thing",
 				Properties: model.Properties{
-					"light": model.Property{
+					"light": {{
 						Value:      100,
 						ObservedAt: &observedTest,
-					},
+					}},
 				},
 			},
 			json: fmt.Sprintf(`{"id":"cabin:4","light":{"observedAt":"%s","type":"Property","value":100},"type":"thing"}`, "2023-02-13T11:30:40.123456Z"),
@@ -166,3 +167,113 @@ func TestMarshalEntity(t *testing.T) {
 	}
 
 }
+
+func TestMarshalAttributeInstanceID(t *testing.T) {
+	instanceID := "urn:instance:1"
+
+	j, err := json.Marshal(model.Relationship{Object: "sensor:1", InstanceID: &instanceID})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"Relationship","object":"sensor:1","instanceId":"urn:instance:1"}`, string(j))
+
+	j, err = json.Marshal(model.Property{Value: 9.4, InstanceID: &instanceID})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"Property","value":9.4,"instanceId":"urn:instance:1"}`, string(j))
+}
+
+func TestSystemAttributes(t *testing.T) {
+	b := []byte(`{
+		"id": "urn:ngsi-ld:Room:1",
+		"type": "Room",
+		"createdAt": "2024-05-01T10:00:00.123456Z",
+		"modifiedAt": "2024-05-02T10:00:00Z",
+		"temperature": {
+			"type": "Property",
+			"value": 21.5,
+			"createdAt": "2024-05-01T10:00:00.123456Z",
+			"modifiedAt": "2024-05-02T10:00:00Z"
+		},
+		"wall": {
+			"type": "Relationship",
+			"object": "urn:wall:1",
+			"deletedAt": "2024-05-03T10:00:00Z"
+		},
+		"name": {
+			"type": "LanguageProperty",
+			"languageMap": {"en": "Kitchen"},
+			"modifiedAt": "2024-05-02T10:00:00Z"
+		}
+	}`)
+
+	e := model.Entity{}
+	err := json.Unmarshal(b, &e)
+	assert.NoError(t, err)
+
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 123456000, time.UTC)
+	modifiedAt := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+	deletedAt := time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, createdAt, *e.CreatedAt)
+	assert.Equal(t, modifiedAt, *e.ModifiedAt)
+	assert.Nil(t, e.DeletedAt)
+	temperature := e.Properties["temperature"][0]
+	assert.Nil(t, temperature.Properties)
+	assert.Equal(t, createdAt, *temperature.CreatedAt)
+	assert.Equal(t, modifiedAt, *temperature.ModifiedAt)
+	assert.Equal(t, deletedAt, *e.Relationships["wall"][0].DeletedAt)
+	assert.Equal(t, modifiedAt, *e.LanguageProperties["name"][0].ModifiedAt)
+
+	j, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), string(j))
+
+	// System attributes can be sent back, but not to create an Entity
+	assert.NoError(t, e.Validate(true))
+	assert.ErrorIs(t, e.ValidateCreation(), model.ErrSystemAttributeNotAllowed)
+	assert.ErrorIs(t, (&model.Entity{ID: "urn:ngsi-ld:Room:1", Type: "Room", DeletedAt: &deletedAt}).ValidateCreation(), model.ErrSystemAttributeNotAllowed)
+	assert.ErrorIs(t, (&model.Entity{
+		ID:   "urn:ngsi-ld:Room:1",
+		Type: "Room",
+		Location: &model.GeoProperty{
+			Value:     geojson.NewPointGeometry([]float64{11.25, 43.77}),
+			DeletedAt: &deletedAt,
+		},
+	}).ValidateCreation(), model.ErrSystemAttributeNotAllowed)
+	assert.ErrorIs(t, (&model.Entity{
+		ID:   "urn:ngsi-ld:Room:1",
+		Type: "Room",
+		Properties: model.Properties{"temperature": {{
+			Value:           21.5,
+			VocabProperties: model.VocabProperties{"scale": {{Vocab: []string{"celsius"}, CreatedAt: &createdAt}}},
+		}}},
+	}).ValidateCreation(), model.ErrSystemAttributeNotAllowed)
+	assert.NoError(t, (&model.Entity{ID: "urn:ngsi-ld:Room:1", Type: "Room"}).ValidateCreation())
+}
+
+func TestEntityScope(t *testing.T) {
+	e := model.Entity{}
+	err := json.Unmarshal([]byte(`{"id": "urn:ngsi-ld:Room:1", "type": "Room", "scope": "/Italy/Tuscany/Florence"}`), &e)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/Italy/Tuscany/Florence"}, e.Scope)
+	assert.Nil(t, e.Properties)
+	assert.NoError(t, e.Validate(true))
+
+	j, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": "urn:ngsi-ld:Room:1", "type": "Room", "scope": "/Italy/Tuscany/Florence"}`, string(j))
+
+	e = model.Entity{}
+	err = json.Unmarshal([]byte(`{"id": "urn:ngsi-ld:Room:1", "type": "Room", "scope": ["/Italy/Tuscany", "/Company/Sales"]}`), &e)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/Italy/Tuscany", "/Company/Sales"}, e.Scope)
+
+	j, err = json.Marshal(e)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": "urn:ngsi-ld:Room:1", "type": "Room", "scope": ["/Italy/Tuscany", "/Company/Sales"]}`, string(j))
+
+	err = json.Unmarshal([]byte(`{"id": "urn:ngsi-ld:Room:1", "type": "Room", "scope": 1}`), &e)
+	assert.ErrorIs(t, err, model.ErrEntityInvalidScope)
+
+	for _, scope := range []string{"", "/", "Italy", "/Italy/", "/Italy//Tuscany", "/Italy/#", "/Italy/+/Florence", "/Italy Tuscany"} {
+		e := model.Entity{ID: "urn:ngsi-ld:Room:1", Type: "Room", Scope: []string{scope}}
+		assert.ErrorIs(t, e.Validate(true), model.ErrEntityInvalidScope, scope)
+	}
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Notification is sent by the Context Broker to the endpoint of a Subscription
+// whenever the watched entities change
+type Notification struct {
+	ID             string    `json:"id"`             // ID of the notification
+	SubscriptionID string    `json:"subscriptionId"` // Subscription originating the notification
+	NotifiedAt     time.Time `json:"notifiedAt"`     // When the notification was sent
+	Data           []Entity  `json:"data"`           // Entities matching the Subscription
+}
@@ -3,20 +3,27 @@ package model
 import (
 	"encoding/json"
 	"reflect"
-	"strings"
 	"time"
-
-	"github.com/pkg/errors"
 )
 
 // Property is an Attribute that holds a value
 type Property struct {
-	Value         any           `json:"value"`
-	Properties    Properties    `json:"-"`
-	Relationships Relationships `json:"-"`
-	ObservedAt    *time.Time    `json:"observedAt,omitempty"`
-	UnitCode      *string       `json:"unitCode,omitempty"`
-	DatasetID     *string       `json:"datasetId,omitempty"`
+	Value              any                `json:"value"`
+	Properties         Properties         `json:"-"`
+	Relationships      Relationships      `json:"-"`
+	GeoProperties      GeoProperties      `json:"-"`
+	LanguageProperties LanguageProperties `json:"-"`
+	VocabProperties    VocabProperties    `json:"-"`
+	JSONProperties     JSONProperties     `json:"-"`
+	ListProperties     ListProperties     `json:"-"`
+	ListRelationships  ListRelationships  `json:"-"`
+	ObservedAt         *time.Time         `json:"observedAt,omitempty"`
+	UnitCode           *string            `json:"unitCode,omitempty"`
+	DatasetID          *string            `json:"datasetId,omitempty"`
+	InstanceID         *string            `json:"instanceId,omitempty"`
+	CreatedAt          *time.Time         `json:"createdAt,omitempty"`
+	ModifiedAt         *time.Time         `json:"modifiedAt,omitempty"`
+	DeletedAt          *time.Time         `json:"deletedAt,omitempty"`
 }
 
 func (p *Property) Type() string {
@@ -27,24 +34,11 @@ func (p Property) MarshalJSON() ([]byte, error) {
 	data := map[string]any{}
 
 	data["type"] = p.Type()
-	data["value"] = p.Value
-	if p.ObservedAt != nil {
-		data["observedAt"] = p.ObservedAt.UTC().Format(timeRFC3339Micro)
-	}
-	if p.DatasetID != nil {
-		data["datasetId"] = p.DatasetID
-	}
+	data["value"] = encodeStructuredValue(p.Value)
 	if p.UnitCode != nil {
 		data["unitCode"] = p.UnitCode
 	}
-
-	for k, v := range p.Properties {
-		data[k] = v
-	}
-
-	for k, v := range p.Relationships {
-		data[k] = v
-	}
+	p.members().marshal(data)
 
 	return json.Marshal(data)
 }
@@ -65,67 +59,12 @@ func (p *Property) UnmarshalJSON(b []byte) error {
 	if d.Value == nil {
 		return ErrPropertyMissingValue
 	}
+	d.Value = decodeStructuredValue(d.Value)
 
-	// Second pass - extract rest of the fields present in the JSON
-	var jsonValues map[string]json.RawMessage
-	_ = json.Unmarshal(b, &jsonValues)
-
-	typ := reflect.TypeOf(d)
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		jsonTag := strings.Split(field.Tag.Get("json"), ",")[0]
-		if jsonTag != "" && jsonTag != "-" {
-			delete(jsonValues, jsonTag)
-		}
-	}
-
-	// Check type or bail out
-	rtype, ok := jsonValues["type"]
-	if !ok || string(rtype) != `"Property"` {
-		return ErrPropertyWrongType
-	}
-	delete(jsonValues, "type")
-
-	// Third pass - partial decode to discover the type of the attribute
-	type Attribute struct {
-		Type string `json:"type,omitempty"`
-	}
-
-	d.Relationships = Relationships{}
-	d.Properties = Properties{}
-
-	for k, v := range jsonValues {
-		a := Attribute{}
-		err := json.Unmarshal(v, &a)
-		if err != nil {
-			return ErrInvalidProperty(errors.Wrapf(err, "cannot unmarshal attribute %s", k))
-		}
-
-		// Fourth pass - decode according to type
-		switch a.Type {
-		case "Relationship":
-			r := Relationship{}
-			err = json.Unmarshal(v, &r)
-			if err != nil {
-				return ErrInvalidProperty(errors.Wrapf(err, "cannot unmarshal relationship %s", k))
-			}
-			d.Relationships[k] = r
-		case "Property":
-			p := Property{}
-			err = json.Unmarshal(v, &p)
-			if err != nil {
-				return ErrInvalidProperty(errors.Wrapf(err, "cannot unmarshal property %s", k))
-			}
-			d.Properties[k] = p
-		}
-	}
-
-	// Do not return empty maps
-	if len(d.Properties) == 0 {
-		d.Properties = nil
-	}
-	if len(d.Relationships) == 0 {
-		d.Relationships = nil
+	// Second pass - check the type and decode the sub-Attributes
+	err := unmarshalSubAttributes(b, "Property", ErrPropertyWrongType, reflect.TypeOf(d), (*Property)(&d).members().subAttributes)
+	if err != nil {
+		return ErrInvalidProperty(err)
 	}
 
 	// Assign fields to pointed structure
@@ -139,18 +78,26 @@ func (p *Property) Validate(strictness bool) ValidationResult {
 		return ErrPropertyMissingValue
 	}
 
-	for _, x := range p.Properties {
-		err := x.Validate(strictness)
-		if err != nil {
-			return err
-		}
-	}
+	return p.members().validate(strictness)
+}
 
-	for _, x := range p.Relationships {
-		err := x.Validate(strictness)
-		if err != nil {
-			return err
-		}
+func (p *Property) members() attributeMembers {
+	return attributeMembers{
+		observedAt: p.ObservedAt,
+		datasetID:  p.DatasetID,
+		instanceID: p.InstanceID,
+		createdAt:  p.CreatedAt,
+		modifiedAt: p.ModifiedAt,
+		deletedAt:  p.DeletedAt,
+		subAttributes: attributeSet{
+			properties:         &p.Properties,
+			relationships:      &p.Relationships,
+			geoProperties:      &p.GeoProperties,
+			languageProperties: &p.LanguageProperties,
+			vocabProperties:    &p.VocabProperties,
+			jsonProperties:     &p.JSONProperties,
+			listProperties:     &p.ListProperties,
+			listRelationships:  &p.ListRelationships,
+		},
 	}
-	return nil
 }
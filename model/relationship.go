@@ -3,18 +3,25 @@ package model
 import (
 	"encoding/json"
 	"reflect"
-	"strings"
 	"time"
-
-	"github.com/pkg/errors"
 )
 
 type Relationship struct {
-	Object        string        `json:"object"`
-	Properties    Properties    `json:"-"`
-	Relationships Relationships `json:"-"`
-	ObservedAt    *time.Time    `json:"observedAt,omitempty"`
-	DatasetID     *string       `json:"datasetId,omitempty"`
+	Object             string             `json:"object"`
+	Properties         Properties         `json:"-"`
+	Relationships      Relationships      `json:"-"`
+	GeoProperties      GeoProperties      `json:"-"`
+	LanguageProperties LanguageProperties `json:"-"`
+	VocabProperties    VocabProperties    `json:"-"`
+	JSONProperties     JSONProperties     `json:"-"`
+	ListProperties     ListProperties     `json:"-"`
+	ListRelationships  ListRelationships  `json:"-"`
+	ObservedAt         *time.Time         `json:"observedAt,omitempty"`
+	DatasetID          *string            `json:"datasetId,omitempty"`
+	InstanceID         *string            `json:"instanceId,omitempty"`
+	CreatedAt          *time.Time         `json:"createdAt,omitempty"`
+	ModifiedAt         *time.Time         `json:"modifiedAt,omitempty"`
+	DeletedAt          *time.Time         `json:"deletedAt,omitempty"`
 }
 
 func (r *Relationship) Type() string {
@@ -26,20 +33,7 @@ func (r Relationship) MarshalJSON() ([]byte, error) {
 
 	data["type"] = r.Type()
 	data["object"] = r.Object
-	if r.ObservedAt != nil {
-		data["observedAt"] = r.ObservedAt.UTC().Format(timeRFC3339Micro)
-	}
-	if r.DatasetID != nil {
-		data["datasetId"] = r.DatasetID
-	}
-
-	for k, v := range r.Properties {
-		data[k] = v
-	}
-
-	for k, v := range r.Relationships {
-		data[k] = v
-	}
+	r.members().marshal(data)
 
 	return json.Marshal(data)
 }
@@ -61,66 +55,10 @@ func (r *Relationship) UnmarshalJSON(b []byte) error {
 		return ErrRelationshipMissingObject
 	}
 
-	// Second pass - extract rest of the fields present in the JSON
-	var jsonValues map[string]json.RawMessage
-	_ = json.Unmarshal(b, &jsonValues)
-
-	typ := reflect.TypeOf(d)
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		jsonTag := strings.Split(field.Tag.Get("json"), ",")[0]
-		if jsonTag != "" && jsonTag != "-" {
-			delete(jsonValues, jsonTag)
-		}
-	}
-
-	// Check type or bail out
-	rtype, ok := jsonValues["type"]
-	if !ok || string(rtype) != `"Relationship"` {
-		return ErrRelationshipWrongType
-	}
-	delete(jsonValues, "type")
-
-	// Third pass - partial decode to discover the type of the attribute
-	type Attribute struct {
-		Type string `json:"type,omitempty"`
-	}
-
-	d.Relationships = Relationships{}
-	d.Properties = Properties{}
-
-	for k, v := range jsonValues {
-		a := Attribute{}
-		err := json.Unmarshal(v, &a)
-		if err != nil {
-			return ErrInvalidRelationship(errors.Wrapf(err, "cannot unmarshal attribute %s", k))
-		}
-
-		// Fourth pass - decode according to type
-		switch a.Type {
-		case "Relationship":
-			r := Relationship{}
-			err = json.Unmarshal(v, &r)
-			if err != nil {
-				return ErrInvalidRelationship(errors.Wrapf(err, "cannot unmarshal relationship %s", k))
-			}
-			d.Relationships[k] = r
-		case "Property":
-			p := Property{}
-			err = json.Unmarshal(v, &p)
-			if err != nil {
-				return ErrInvalidRelationship(errors.Wrapf(err, "cannot unmarshal property %s", k))
-			}
-			d.Properties[k] = p
-		}
-	}
-
-	// Do not return empty maps
-	if len(d.Properties) == 0 {
-		d.Properties = nil
-	}
-	if len(d.Relationships) == 0 {
-		d.Relationships = nil
+	// Second pass - check the type and decode the sub-Attributes
+	err := unmarshalSubAttributes(b, "Relationship", ErrRelationshipWrongType, reflect.TypeOf(d), (*Relationship)(&d).members().subAttributes)
+	if err != nil {
+		return ErrInvalidRelationship(err)
 	}
 
 	// Assign fields to pointed structure
@@ -134,17 +72,26 @@ func (r *Relationship) Validate(strictness bool) ValidationResult {
 		return ErrRelationshipMissingObject
 	}
 
-	for _, x := range r.Properties {
-		err := x.Validate(strictness)
-		if err != nil {
-			return err
-		}
-	}
-	for _, x := range r.Relationships {
-		err := x.Validate(strictness)
-		if err != nil {
-			return err
-		}
+	return r.members().validate(strictness)
+}
+
+func (r *Relationship) members() attributeMembers {
+	return attributeMembers{
+		observedAt: r.ObservedAt,
+		datasetID:  r.DatasetID,
+		instanceID: r.InstanceID,
+		createdAt:  r.CreatedAt,
+		modifiedAt: r.ModifiedAt,
+		deletedAt:  r.DeletedAt,
+		subAttributes: attributeSet{
+			properties:         &r.Properties,
+			relationships:      &r.Relationships,
+			geoProperties:      &r.GeoProperties,
+			languageProperties: &r.LanguageProperties,
+			vocabProperties:    &r.VocabProperties,
+			jsonProperties:     &r.JSONProperties,
+			listProperties:     &r.ListProperties,
+			listRelationships:  &r.ListRelationships,
+		},
 	}
-	return nil
 }
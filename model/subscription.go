@@ -0,0 +1,137 @@
+package model
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+// Subscription asks the Context Broker to notify an endpoint about the changes of the entities it selects
+// https://www.etsi.org/deliver/etsi_gs/CIM/001_099/009/01.06.01_60/gs_cim009v010601p.pdf (5.2.12)
+type Subscription struct {
+	ID                string              `json:"id,omitempty"`                // Assigned by the Context Broker when missing
+	SubscriptionName  string              `json:"subscriptionName,omitempty"`  // Name given by the user
+	Description       string              `json:"description,omitempty"`       // Description given by the user
+	Entities          []EntitySelector    `json:"entities,omitempty"`          // Entities to watch
+	WatchedAttributes []string            `json:"watchedAttributes,omitempty"` // Attributes whose change triggers a notification
+	TimeInterval      *int                `json:"timeInterval,omitempty"`      // Seconds between periodic notifications
+	Q                 string              `json:"q,omitempty"`                 // Query the entities must satisfy, see package query
+	GeoQ              *GeoQuery           `json:"geoQ,omitempty"`              // Geospatial query the entities must satisfy
+	ScopeQ            string              `json:"scopeQ,omitempty"`            // Query the scope of the entities must satisfy, see package query
+	CSF               string              `json:"csf,omitempty"`               // Context source filter
+	IsActive          *bool               `json:"isActive,omitempty"`          // Paused subscriptions are not active
+	Notification      *NotificationParams `json:"notification,omitempty"`      // How notifications are delivered
+	ExpiresAt         *time.Time          `json:"expiresAt,omitempty"`         // End of the subscription
+	Throttling        *int                `json:"throttling,omitempty"`        // Minimum seconds between two notifications
+	Status            string              `json:"status,omitempty"`            // Set by the Context Broker: active, paused or expired
+}
+
+// EntitySelector identifies the entities of a Subscription by type, and optionally ID or ID pattern
+type EntitySelector struct {
+	ID        string `json:"id,omitempty"`
+	IDPattern string `json:"idPattern,omitempty"`
+	Type      string `json:"type"`
+}
+
+// NotificationParams describes the content of the notifications and where they are delivered
+type NotificationParams struct {
+	Attributes []string `json:"attributes,omitempty"` // Attributes to include, all when missing
+	Format     string   `json:"format,omitempty"`     // normalized (default) or keyValues
+	Endpoint   Endpoint `json:"endpoint"`
+
+	// Set by the Context Broker
+	Status           string     `json:"status,omitempty"`
+	TimesSent        int        `json:"timesSent,omitempty"`
+	LastNotification *time.Time `json:"lastNotification,omitempty"`
+	LastFailure      *time.Time `json:"lastFailure,omitempty"`
+	LastSuccess      *time.Time `json:"lastSuccess,omitempty"`
+}
+
+// Endpoint receives the notifications of a Subscription
+type Endpoint struct {
+	URI    string `json:"uri"`
+	Accept string `json:"accept,omitempty"` // MIME type of the notifications
+}
+
+const (
+	NotificationFormatNormalized = "normalized"
+	NotificationFormatKeyValues  = "keyValues"
+)
+
+func (s Subscription) MarshalJSON() ([]byte, error) {
+	// Use an alias to avoid recursion into this function
+	type writeSubscription Subscription
+
+	data := struct {
+		writeSubscription
+		Type      string  `json:"type"`
+		ExpiresAt *string `json:"expiresAt,omitempty"`
+	}{
+		writeSubscription: writeSubscription(s),
+		Type:              "Subscription",
+	}
+	if s.ExpiresAt != nil {
+		expiresAt := s.ExpiresAt.UTC().Format(TimeRFC3339Micro)
+		data.ExpiresAt = &expiresAt
+	}
+
+	return json.Marshal(data)
+}
+
+func (s *Subscription) Validate(strictness bool) ValidationResult {
+	if len(s.Entities) == 0 && len(s.WatchedAttributes) == 0 {
+		return ErrSubscriptionMissingTarget
+	}
+	for _, e := range s.Entities {
+		if e.Type == "" || (e.ID != "" && e.IDPattern != "") {
+			return ErrSubscriptionInvalidEntitySelector
+		}
+	}
+
+	if s.TimeInterval != nil && (*s.TimeInterval <= 0 || len(s.WatchedAttributes) > 0 || s.Throttling != nil) {
+		return ErrSubscriptionInvalidTimeInterval
+	}
+	if s.Throttling != nil && *s.Throttling <= 0 {
+		return ErrSubscriptionInvalidThrottling
+	}
+
+	if s.GeoQ != nil {
+		err := s.GeoQ.Validate(strictness)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.Notification == nil {
+		return ErrSubscriptionMissingNotification
+	}
+	err := s.Notification.Validate(strictness)
+	if err != nil {
+		return err
+	}
+
+	if strictness && s.ExpiresAt != nil && !s.ExpiresAt.After(time.Now()) {
+		return ErrSubscriptionExpired
+	}
+	return nil
+}
+
+func (n *NotificationParams) Validate(strictness bool) ValidationResult {
+	switch n.Format {
+	case "", NotificationFormatNormalized, NotificationFormatKeyValues:
+	default:
+		return ErrSubscriptionInvalidFormat
+	}
+
+	endpoint, err := url.Parse(n.Endpoint.URI)
+	if err != nil || !endpoint.IsAbs() {
+		return ErrSubscriptionInvalidEndpoint
+	}
+
+	switch n.Endpoint.Accept {
+	case "", "application/json", "application/ld+json", "application/geo+json":
+	default:
+		return ErrSubscriptionInvalidAccept
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSubscription() model.Subscription {
+	return model.Subscription{
+		Entities: []model.EntitySelector{{Type: "Room"}},
+		Notification: &model.NotificationParams{
+			Endpoint: model.Endpoint{URI: "http://receiver:8080/notify"},
+		},
+	}
+}
+
+func TestMarshalSubscription(t *testing.T) {
+	expiresAt, err := time.Parse(time.RFC3339Nano, "2030-02-13T11:30:40.123456789Z")
+	assert.NoError(t, err)
+	throttling := 5
+
+	s := testSubscription()
+	s.ID = "urn:subscription:1"
+	s.WatchedAttributes = []string{"temperature"}
+	s.Q = "temperature>20"
+	s.ScopeQ = "/Italy/#"
+	s.Throttling = &throttling
+	s.ExpiresAt = &expiresAt
+	s.Notification.Format = model.NotificationFormatKeyValues
+
+	j, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+    "id": "urn:subscription:1",
+    "type": "Subscription",
+    "entities": [{"type": "Room"}],
+    "watchedAttributes": ["temperature"],
+    "q": "temperature>20",
+    "scopeQ": "/Italy/#",
+    "throttling": 5,
+    "expiresAt": "2030-02-13T11:30:40.123456Z",
+    "notification": {"format": "keyValues", "endpoint": {"uri": "http://receiver:8080/notify"}}
+  }`, string(j))
+
+	// Round trip
+	r := model.Subscription{}
+	assert.NoError(t, json.Unmarshal(j, &r))
+	assert.Equal(t, s.Entities, r.Entities)
+	assert.Equal(t, s.Notification.Endpoint, r.Notification.Endpoint)
+	assert.True(t, expiresAt.Truncate(time.Microsecond).Equal(*r.ExpiresAt))
+}
+
+func TestSubscriptionValidation(t *testing.T) {
+	type testCase struct {
+		name   string
+		modify func(s *model.Subscription)
+		err    error
+	}
+
+	interval := 60
+	negative := -1
+	past := time.Now().Add(-time.Hour)
+
+	tests := []testCase{
+		{
+			name:   "valid",
+			modify: func(s *model.Subscription) {},
+		},
+		{
+			name:   "missing target",
+			modify: func(s *model.Subscription) { s.Entities = nil },
+			err:    model.ErrSubscriptionMissingTarget,
+		},
+		{
+			name:   "entity selector without type",
+			modify: func(s *model.Subscription) { s.Entities = []model.EntitySelector{{ID: "urn:room:1"}} },
+			err:    model.ErrSubscriptionInvalidEntitySelector,
+		},
+		{
+			name:   "missing notification",
+			modify: func(s *model.Subscription) { s.Notification = nil },
+			err:    model.ErrSubscriptionMissingNotification,
+		},
+		{
+			name:   "relative endpoint",
+			modify: func(s *model.Subscription) { s.Notification.Endpoint.URI = "/notify" },
+			err:    model.ErrSubscriptionInvalidEndpoint,
+		},
+		{
+			name:   "unknown format",
+			modify: func(s *model.Subscription) { s.Notification.Format = "concise" },
+			err:    model.ErrSubscriptionInvalidFormat,
+		},
+		{
+			name:   "unknown accept",
+			modify: func(s *model.Subscription) { s.Notification.Endpoint.Accept = "text/plain" },
+			err:    model.ErrSubscriptionInvalidAccept,
+		},
+		{
+			name: "time interval with watched attributes",
+			modify: func(s *model.Subscription) {
+				s.TimeInterval = &interval
+				s.WatchedAttributes = []string{"temperature"}
+			},
+			err: model.ErrSubscriptionInvalidTimeInterval,
+		},
+		{
+			name:   "negative throttling",
+			modify: func(s *model.Subscription) { s.Throttling = &negative },
+			err:    model.ErrSubscriptionInvalidThrottling,
+		},
+		{
+			name:   "invalid geoquery",
+			modify: func(s *model.Subscription) { s.GeoQ = &model.GeoQuery{Geometry: "Point", Georel: "within"} },
+			err:    model.ErrGeoQueryMissingCoordinates,
+		},
+		{
+			name:   "expired",
+			modify: func(s *model.Subscription) { s.ExpiresAt = &past },
+			err:    model.ErrSubscriptionExpired,
+		},
+	}
+
+	for _, y := range tests {
+		t.Run(y.name, func(t *testing.T) {
+			s := testSubscription()
+			y.modify(&s)
+			err := s.Validate(true)
+			if y.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, y.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TemporalProperties is a helper type, defines the instances in time of a set of Properties
+type TemporalProperties map[string][]Property
+
+// TemporalRelationships is a helper type, defines the instances in time of a set of Relationships
+type TemporalRelationships map[string][]Relationship
+
+// TemporalGeoProperties is a helper type, defines the instances in time of a set of GeoProperties
+type TemporalGeoProperties map[string][]GeoProperty
+
+// TemporalLanguageProperties is a helper type, defines the instances in time of a set of LanguageProperties
+type TemporalLanguageProperties map[string][]LanguageProperty
+
+// TemporalVocabProperties is a helper type, defines the instances in time of a set of VocabProperties
+type TemporalVocabProperties map[string][]VocabProperty
+
+// TemporalJSONProperties is a helper type, defines the instances in time of a set of JsonProperties
+type TemporalJSONProperties map[string][]JSONProperty
+
+// TemporalListProperties is a helper type, defines the instances in time of a set of ListProperties
+type TemporalListProperties map[string][]ListProperty
+
+// TemporalListRelationships is a helper type, defines the instances in time of a set of ListRelationships
+type TemporalListRelationships map[string][]ListRelationship
+
+// TemporalEntity is the evolution in time of an Entity: each Attribute holds a
+// list of instances, usually told apart by their observedAt
+type TemporalEntity struct {
+	ID                 string                     `json:"id"`                   // ID of the entity used to identify the single entity
+	Type               string                     `json:"type"`                 // Type of the entity used for categorization
+	Scope              []string                   `json:"-"`                    // Hierarchical scopes of the entity, e.g. /Italy/Tuscany
+	Properties         TemporalProperties         `json:"-"`                    // Instances of the values that define the entity
+	Relationships      TemporalRelationships      `json:"-"`                    // Instances of the links to other entities
+	GeoProperties      TemporalGeoProperties      `json:"-"`                    // Instances of the geographical attributes (e.g. location)
+	LanguageProperties TemporalLanguageProperties `json:"-"`                    // Instances of the strings in several languages
+	VocabProperties    TemporalVocabProperties    `json:"-"`                    // Instances of the terms of the @context
+	JSONProperties     TemporalJSONProperties     `json:"-"`                    // Instances of the JSON values not interpreted as JSON-LD
+	ListProperties     TemporalListProperties     `json:"-"`                    // Instances of the ordered lists of values
+	ListRelationships  TemporalListRelationships  `json:"-"`                    // Instances of the links to ordered lists of entities
+	CreatedAt          *time.Time                 `json:"createdAt,omitempty"`  // Creation time, set by the Context Broker
+	ModifiedAt         *time.Time                 `json:"modifiedAt,omitempty"` // Last modification time, set by the Context Broker
+	DeletedAt          *time.Time                 `json:"deletedAt,omitempty"`  // Deletion time, set by the Context Broker
+}
+
+// TemporalQuery selects the instances of the Attributes by time
+type TemporalQuery struct {
+	Timerel      string     `json:"timerel"`                // before, after or between
+	TimeAt       time.Time  `json:"timeAt"`                 // Reference time
+	EndTimeAt    *time.Time `json:"endTimeAt,omitempty"`    // End of the interval for between
+	TimeProperty string     `json:"timeproperty,omitempty"` // observedAt (default), createdAt or modifiedAt
+}
+
+const (
+	TimerelBefore  = "before"
+	TimerelAfter   = "after"
+	TimerelBetween = "between"
+)
+
+func (e TemporalEntity) MarshalJSON() ([]byte, error) {
+	data := map[string]any{}
+
+	data["type"] = e.Type
+	data["id"] = e.ID
+	marshalScope(data, e.Scope)
+	marshalSystemAttributes(data, e.CreatedAt, e.ModifiedAt, e.DeletedAt)
+
+	e.attributes().marshal(data)
+
+	return json.Marshal(data)
+}
+
+func (e *TemporalEntity) UnmarshalJSON(b []byte) error {
+	// Use an alias to avoid recursion into this function
+	type readTemporalEntity TemporalEntity
+
+	d := readTemporalEntity{}
+
+	// First pass - extract annotated fields
+	if err := json.Unmarshal(b, &d); err != nil {
+		return ErrInvalidTemporalEntity(err)
+	}
+
+	// Check for missing mandatory values
+	if d.ID == "" {
+		return ErrEntityMissingID
+	}
+	if d.Type == "" {
+		return ErrEntityMissingType
+	}
+
+	// Second pass - extract rest of the fields present in the JSON
+	var jsonValues map[string]json.RawMessage
+	_ = json.Unmarshal(b, &jsonValues)
+
+	typ := reflect.TypeOf(d)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		jsonTag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if jsonTag != "" && jsonTag != "-" {
+			delete(jsonValues, jsonTag)
+		}
+	}
+	delete(jsonValues, "@context")
+
+	scope, err := unmarshalScope(jsonValues)
+	if err != nil {
+		return err
+	}
+	d.Scope = scope
+
+	// Third pass - partial decode to discover the type of the instances
+	type Attribute struct {
+		Type string `json:"type,omitempty"`
+	}
+
+	for k, v := range jsonValues {
+		// A single instance may not be wrapped in a list
+		instances := []json.RawMessage{}
+		if trimmed := bytes.TrimSpace(v); len(trimmed) > 0 && trimmed[0] == '[' {
+			err := json.Unmarshal(v, &instances)
+			if err != nil {
+				return ErrInvalidTemporalEntity(errors.Wrapf(err, "cannot unmarshal attribute %s", k))
+			}
+		} else {
+			instances = append(instances, v)
+		}
+		if len(instances) == 0 {
+			continue
+		}
+
+		attributeType := ""
+		for _, instance := range instances {
+			a := Attribute{}
+			err := json.Unmarshal(instance, &a)
+			if err != nil {
+				return ErrInvalidTemporalEntity(errors.Wrapf(err, "cannot unmarshal attribute %s", k))
+			}
+			if attributeType != "" && a.Type != attributeType {
+				return ErrInvalidTemporalEntity(errors.Wrapf(ErrTemporalEntityMixedTypes, "attribute %s", k))
+			}
+			attributeType = a.Type
+		}
+
+		// Fourth pass - decode according to type
+		err = (*TemporalEntity)(&d).attributes().decode(k, attributeType, instances)
+		if err != nil {
+			return ErrInvalidTemporalEntity(err)
+		}
+	}
+
+	// Assign fields to pointed structure
+	*e = TemporalEntity(d)
+
+	return nil
+}
+
+func (e *TemporalEntity) Validate(strictness bool) ValidationResult {
+	if len(e.ID) == 0 {
+		return ErrEntityMissingID
+	}
+	if len(e.Type) == 0 {
+		return ErrEntityMissingType
+	}
+
+	return e.attributes().validate(strictness)
+}
+
+func (q *TemporalQuery) Validate(strictness bool) ValidationResult {
+	switch q.Timerel {
+	case TimerelBefore, TimerelAfter:
+		if q.EndTimeAt != nil {
+			return ErrTemporalQueryInvalidEndTime
+		}
+	case TimerelBetween:
+		if q.EndTimeAt == nil || !q.EndTimeAt.After(q.TimeAt) {
+			return ErrTemporalQueryInvalidEndTime
+		}
+	default:
+		return ErrTemporalQueryInvalidTimerel
+	}
+
+	if q.TimeAt.IsZero() {
+		return ErrTemporalQueryMissingTimeAt
+	}
+
+	switch q.TimeProperty {
+	case "", "observedAt", "createdAt", "modifiedAt":
+	default:
+		return ErrTemporalQueryInvalidProperty
+	}
+	return nil
+}
+
+// TemporalEntityFragment is a set of temporal Attributes of an Entity, without its ID and type.
+// It is the payload of the operation appending instances to the Attributes.
+type TemporalEntityFragment struct {
+	Properties         TemporalProperties
+	Relationships      TemporalRelationships
+	GeoProperties      TemporalGeoProperties
+	LanguageProperties TemporalLanguageProperties
+	VocabProperties    TemporalVocabProperties
+	JSONProperties     TemporalJSONProperties
+	ListProperties     TemporalListProperties
+	ListRelationships  TemporalListRelationships
+}
+
+func (f TemporalEntityFragment) MarshalJSON() ([]byte, error) {
+	data := map[string]any{}
+	f.attributes().marshal(data)
+	return json.Marshal(data)
+}
+
+// Names returns the sorted names of the Attributes in the fragment
+func (f *TemporalEntityFragment) Names() []string {
+	names := f.attributes().names()
+	sort.Strings(names)
+	return names
+}
+
+func (f *TemporalEntityFragment) Validate(strictness bool) ValidationResult {
+	if len(f.attributes().names()) == 0 {
+		return ErrEntityFragmentEmpty
+	}
+
+	return f.attributes().validate(strictness)
+}
+
+// temporalAttributeSet points to the temporal Attributes of an Entity, one set for each kind of Attribute
+type temporalAttributeSet struct {
+	properties         *TemporalProperties
+	relationships      *TemporalRelationships
+	geoProperties      *TemporalGeoProperties
+	languageProperties *TemporalLanguageProperties
+	vocabProperties    *TemporalVocabProperties
+	jsonProperties     *TemporalJSONProperties
+	listProperties     *TemporalListProperties
+	listRelationships  *TemporalListRelationships
+}
+
+func (e *TemporalEntity) attributes() temporalAttributeSet {
+	return temporalAttributeSet{
+		properties:         &e.Properties,
+		relationships:      &e.Relationships,
+		geoProperties:      &e.GeoProperties,
+		languageProperties: &e.LanguageProperties,
+		vocabProperties:    &e.VocabProperties,
+		jsonProperties:     &e.JSONProperties,
+		listProperties:     &e.ListProperties,
+		listRelationships:  &e.ListRelationships,
+	}
+}
+
+func (f *TemporalEntityFragment) attributes() temporalAttributeSet {
+	return temporalAttributeSet{
+		properties:         &f.Properties,
+		relationships:      &f.Relationships,
+		geoProperties:      &f.GeoProperties,
+		languageProperties: &f.LanguageProperties,
+		vocabProperties:    &f.VocabProperties,
+		jsonProperties:     &f.JSONProperties,
+		listProperties:     &f.ListProperties,
+		listRelationships:  &f.ListRelationships,
+	}
+}
+
+// decode decodes the instances of the Attribute named name into the set of its kind.
+// Instances of unknown kinds are skipped, sets without Attributes are left nil.
+func (s temporalAttributeSet) decode(name, typeName string, instances []json.RawMessage) error {
+	switch typeName {
+	case "Property":
+		return decodeTemporalInstances(s.properties, name, instances, "property")
+	case "Relationship":
+		return decodeTemporalInstances(s.relationships, name, instances, "relationship")
+	case "GeoProperty":
+		return decodeTemporalInstances(s.geoProperties, name, instances, "geoproperty")
+	case "LanguageProperty":
+		return decodeTemporalInstances(s.languageProperties, name, instances, "language property")
+	case "VocabProperty":
+		return decodeTemporalInstances(s.vocabProperties, name, instances, "vocab property")
+	case "JsonProperty":
+		return decodeTemporalInstances(s.jsonProperties, name, instances, "JSON property")
+	case "ListProperty":
+		return decodeTemporalInstances(s.listProperties, name, instances, "list property")
+	case "ListRelationship":
+		return decodeTemporalInstances(s.listRelationships, name, instances, "list relationship")
+	}
+	return nil
+}
+
+// decodeTemporalInstances decodes the instances in time of the Attribute named name into the set
+func decodeTemporalInstances[M ~map[string][]T, T any](attributes *M, name string, instances []json.RawMessage, kind string) error {
+	values := make([]T, 0, len(instances))
+	for _, instance := range instances {
+		var v T
+		err := json.Unmarshal(instance, &v)
+		if err != nil {
+			return errors.Wrapf(err, "cannot unmarshal %s %s", kind, name)
+		}
+		values = append(values, v)
+	}
+	if *attributes == nil {
+		*attributes = M{}
+	}
+	(*attributes)[name] = values
+	return nil
+}
+
+// marshal adds every temporal Attribute of the sets to the JSON object
+func (s temporalAttributeSet) marshal(data map[string]any) {
+	marshalAttributes(data, *s.properties)
+	marshalAttributes(data, *s.relationships)
+	marshalAttributes(data, *s.geoProperties)
+	marshalAttributes(data, *s.languageProperties)
+	marshalAttributes(data, *s.vocabProperties)
+	marshalAttributes(data, *s.jsonProperties)
+	marshalAttributes(data, *s.listProperties)
+	marshalAttributes(data, *s.listRelationships)
+}
+
+// names returns the names of the temporal Attributes of the sets
+func (s temporalAttributeSet) names() []string {
+	names := []string{}
+	names = appendNames(names, *s.properties)
+	names = appendNames(names, *s.relationships)
+	names = appendNames(names, *s.geoProperties)
+	names = appendNames(names, *s.languageProperties)
+	names = appendNames(names, *s.vocabProperties)
+	names = appendNames(names, *s.jsonProperties)
+	names = appendNames(names, *s.listProperties)
+	names = appendNames(names, *s.listRelationships)
+	return names
+}
+
+// validate checks every instance of the temporal Attributes of the sets
+func (s temporalAttributeSet) validate(strictness bool) ValidationResult {
+	for _, err := range []ValidationResult{
+		validateTemporalInstances(*s.properties, strictness),
+		validateTemporalInstances(*s.relationships, strictness),
+		validateTemporalInstances(*s.geoProperties, strictness),
+		validateTemporalInstances(*s.languageProperties, strictness),
+		validateTemporalInstances(*s.vocabProperties, strictness),
+		validateTemporalInstances(*s.jsonProperties, strictness),
+		validateTemporalInstances(*s.listProperties, strictness),
+		validateTemporalInstances(*s.listRelationships, strictness),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatable is implemented by the pointers to the kinds of Attribute
+type validatable[T any] interface {
+	*T
+	Validatable
+}
+
+// validateTemporalInstances checks every instance in time of the set of Attributes
+func validateTemporalInstances[M ~map[string][]T, T any, PT validatable[T]](attributes M, strictness bool) ValidationResult {
+	for _, instances := range attributes {
+		for i := range instances {
+			err := PT(&instances[i]).Validate(strictness)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
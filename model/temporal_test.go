@@ -0,0 +1,278 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalTemporalEntity(t *testing.T) {
+	first, err := time.Parse(time.RFC3339, "2022-03-01T10:00:00Z")
+	assert.NoError(t, err)
+	second, err := time.Parse(time.RFC3339, "2022-03-01T11:00:00Z")
+	assert.NoError(t, err)
+
+	var e model.TemporalEntity
+	err = json.Unmarshal([]byte(`{
+    "@context": "https://uri.etsi.org/ngsi-ld/v1/ngsi-ld-core-context.jsonld",
+    "id": "urn:room:1",
+    "type": "Room",
+    "temperature": [
+      {"type": "Property", "value": 21.5, "observedAt": "2022-03-01T10:00:00Z"},
+      {"type": "Property", "value": 22, "observedAt": "2022-03-01T11:00:00Z"}
+    ],
+    "wall": {"type": "Relationship", "object": "urn:wall:1", "observedAt": "2022-03-01T10:00:00Z"}
+  }`), &e)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TemporalEntity{
+		ID:   "urn:room:1",
+		Type: "Room",
+		Properties: model.TemporalProperties{
+			"temperature": {
+				{Value: 21.5, ObservedAt: &first},
+				{Value: float64(22), ObservedAt: &second},
+			},
+		},
+		Relationships: model.TemporalRelationships{
+			"wall": {{Object: "urn:wall:1", ObservedAt: &first}},
+		},
+	}, e)
+}
+
+func TestUnmarshalTemporalEntityKinds(t *testing.T) {
+	first, err := time.Parse(time.RFC3339, "2022-03-01T10:00:00Z")
+	assert.NoError(t, err)
+	second, err := time.Parse(time.RFC3339, "2022-03-01T11:00:00Z")
+	assert.NoError(t, err)
+
+	input := `{
+    "id": "urn:room:1",
+    "type": "Room",
+    "name": [
+      {"type": "LanguageProperty", "languageMap": {"en": "Blue room", "it": "Stanza blu"}, "observedAt": "2022-03-01T10:00:00Z"},
+      {"type": "LanguageProperty", "languageMap": {"en": "Red room"}, "observedAt": "2022-03-01T11:00:00Z"}
+    ],
+    "readings": [
+      {"type": "ListProperty", "valueList": [21.5, 22], "observedAt": "2022-03-01T10:00:00Z"}
+    ],
+    "category": {"type": "VocabProperty", "vocab": "meeting", "observedAt": "2022-03-01T10:00:00Z"},
+    "config": {"type": "JsonProperty", "json": {"mode": "eco"}, "observedAt": "2022-03-01T10:00:00Z"},
+    "sensors": {"type": "ListRelationship", "objectList": [{"object": "urn:sensor:1"}], "observedAt": "2022-03-01T10:00:00Z"}
+  }`
+
+	var e model.TemporalEntity
+	err = json.Unmarshal([]byte(input), &e)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TemporalLanguageProperties{
+		"name": {
+			{LanguageMap: map[string]string{"en": "Blue room", "it": "Stanza blu"}, ObservedAt: &first},
+			{LanguageMap: map[string]string{"en": "Red room"}, ObservedAt: &second},
+		},
+	}, e.LanguageProperties)
+	assert.Equal(t, model.TemporalListProperties{
+		"readings": {{ValueList: []any{21.5, float64(22)}, ObservedAt: &first}},
+	}, e.ListProperties)
+	assert.Len(t, e.VocabProperties["category"], 1)
+	assert.Len(t, e.JSONProperties["config"], 1)
+	assert.Len(t, e.ListRelationships["sensors"], 1)
+	assert.Nil(t, e.Properties)
+	assert.NoError(t, e.Validate(true))
+
+	b, err := json.Marshal(e)
+	assert.NoError(t, err)
+	var decoded model.TemporalEntity
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, e, decoded)
+}
+
+func TestUnmarshalTemporalEntitySystemAttributes(t *testing.T) {
+	created, err := time.Parse(time.RFC3339, "2022-03-01T09:00:00.123456Z")
+	assert.NoError(t, err)
+	modified, err := time.Parse(time.RFC3339, "2022-03-01T11:00:00Z")
+	assert.NoError(t, err)
+
+	var e model.TemporalEntity
+	err = json.Unmarshal([]byte(`{
+    "id": "urn:room:1",
+    "type": "Room",
+    "createdAt": "2022-03-01T09:00:00.123456Z",
+    "modifiedAt": "2022-03-01T11:00:00Z",
+    "temperature": [
+      {"type": "Property", "value": 21.5, "createdAt": "2022-03-01T09:00:00.123456Z", "modifiedAt": "2022-03-01T11:00:00Z"}
+    ]
+  }`), &e)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TemporalEntity{
+		ID:   "urn:room:1",
+		Type: "Room",
+		Properties: model.TemporalProperties{
+			"temperature": {{Value: 21.5, CreatedAt: &created, ModifiedAt: &modified}},
+		},
+		CreatedAt:  &created,
+		ModifiedAt: &modified,
+	}, e)
+
+	b, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+    "id": "urn:room:1",
+    "type": "Room",
+    "createdAt": "2022-03-01T09:00:00.123456Z",
+    "modifiedAt": "2022-03-01T11:00:00Z",
+    "temperature": [
+      {"type": "Property", "value": 21.5, "createdAt": "2022-03-01T09:00:00.123456Z", "modifiedAt": "2022-03-01T11:00:00Z"}
+    ]
+  }`, string(b))
+}
+
+func TestUnmarshalTemporalEntityScope(t *testing.T) {
+	tests := map[string]struct {
+		scope    string
+		expected []string
+	}{
+		"single scope":    {scope: `"/Italy/Tuscany"`, expected: []string{"/Italy/Tuscany"}},
+		"several scopes":  {scope: `["/Italy/Tuscany", "/Company/Sales"]`, expected: []string{"/Italy/Tuscany", "/Company/Sales"}},
+		"scope not found": {scope: "", expected: nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scope := ""
+			if tc.scope != "" {
+				scope = `"scope": ` + tc.scope + `,`
+			}
+
+			var e model.TemporalEntity
+			err := json.Unmarshal([]byte(`{
+        "id": "urn:room:1",
+        "type": "Room",
+        `+scope+`
+        "temperature": [{"type": "Property", "value": 21.5}]
+      }`), &e)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, e.Scope)
+			assert.Len(t, e.Properties["temperature"], 1)
+
+			b, err := json.Marshal(e)
+			assert.NoError(t, err)
+			var decoded model.TemporalEntity
+			assert.NoError(t, json.Unmarshal(b, &decoded))
+			assert.Equal(t, e, decoded)
+		})
+	}
+
+	var e model.TemporalEntity
+	err := json.Unmarshal([]byte(`{"id": "urn:room:1", "type": "Room", "scope": 42}`), &e)
+	assert.ErrorIs(t, err, model.ErrEntityInvalidScope)
+}
+
+func TestUnmarshalTemporalEntityErrors(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		err   error
+	}{
+		"missing id": {
+			input: `{"type": "Room"}`,
+			err:   model.ErrEntityMissingID,
+		},
+		"missing type": {
+			input: `{"id": "urn:room:1"}`,
+			err:   model.ErrEntityMissingType,
+		},
+		"mixed types": {
+			input: `{"id": "urn:room:1", "type": "Room", "temperature": [
+        {"type": "Property", "value": 21.5},
+        {"type": "Relationship", "object": "urn:wall:1"}
+      ]}`,
+			err: model.ErrTemporalEntityMixedTypes,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var e model.TemporalEntity
+			err := json.Unmarshal([]byte(tc.input), &e)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestMarshalTemporalEntity(t *testing.T) {
+	observedAt, err := time.Parse(time.RFC3339, "2022-03-01T10:00:00Z")
+	assert.NoError(t, err)
+
+	e := model.TemporalEntity{
+		ID:   "urn:room:1",
+		Type: "Room",
+		Properties: model.TemporalProperties{
+			"temperature": {{Value: 21.5, ObservedAt: &observedAt}},
+		},
+	}
+	b, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+    "id": "urn:room:1",
+    "type": "Room",
+    "temperature": [{"type": "Property", "value": 21.5, "observedAt": "2022-03-01T10:00:00Z"}]
+  }`, string(b))
+
+	var decoded model.TemporalEntity
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, e, decoded)
+}
+
+func TestValidateTemporalQuery(t *testing.T) {
+	timeAt := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	endTimeAt := timeAt.Add(time.Hour)
+	before := timeAt.Add(-time.Hour)
+
+	tests := map[string]struct {
+		query model.TemporalQuery
+		err   error
+	}{
+		"before": {
+			query: model.TemporalQuery{Timerel: model.TimerelBefore, TimeAt: timeAt},
+		},
+		"between": {
+			query: model.TemporalQuery{Timerel: model.TimerelBetween, TimeAt: timeAt, EndTimeAt: &endTimeAt, TimeProperty: "modifiedAt"},
+		},
+		"invalid timerel": {
+			query: model.TemporalQuery{Timerel: "during", TimeAt: timeAt},
+			err:   model.ErrTemporalQueryInvalidTimerel,
+		},
+		"missing timeAt": {
+			query: model.TemporalQuery{Timerel: model.TimerelAfter},
+			err:   model.ErrTemporalQueryMissingTimeAt,
+		},
+		"between without end": {
+			query: model.TemporalQuery{Timerel: model.TimerelBetween, TimeAt: timeAt},
+			err:   model.ErrTemporalQueryInvalidEndTime,
+		},
+		"between with end before start": {
+			query: model.TemporalQuery{Timerel: model.TimerelBetween, TimeAt: timeAt, EndTimeAt: &before},
+			err:   model.ErrTemporalQueryInvalidEndTime,
+		},
+		"after with end": {
+			query: model.TemporalQuery{Timerel: model.TimerelAfter, TimeAt: timeAt, EndTimeAt: &endTimeAt},
+			err:   model.ErrTemporalQueryInvalidEndTime,
+		},
+		"invalid time property": {
+			query: model.TemporalQuery{Timerel: model.TimerelAfter, TimeAt: timeAt, TimeProperty: "deletedAt"},
+			err:   model.ErrTemporalQueryInvalidProperty,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.query.Validate(true)
+			if tc.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,428 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Aggregation methods of the aggregated temporal representation
+const (
+	AggrTotalCount    = "totalCount"
+	AggrDistinctCount = "distinctCount"
+	AggrSum           = "sum"
+	AggrAvg           = "avg"
+	AggrMin           = "min"
+	AggrMax           = "max"
+	AggrStdDev        = "stddev"
+	AggrSumSq         = "sumsq"
+)
+
+// TimePoint is a value of an Attribute at a given time
+type TimePoint struct {
+	Time  time.Time
+	Value any
+}
+
+// TimeSeries is the simplified temporal representation of an Attribute,
+// a list of values with their timestamp
+type TimeSeries []TimePoint
+
+// DatasetTimeSeries are the time series of the instances of an Attribute by datasetId.
+// The default instance has an empty datasetId.
+type DatasetTimeSeries map[string]TimeSeries
+
+// Float64Point is a numeric value of an Attribute at a given time
+type Float64Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// StringPoint is a string value of an Attribute at a given time
+type StringPoint struct {
+	Time  time.Time
+	Value string
+}
+
+// BoolPoint is a boolean value of an Attribute at a given time
+type BoolPoint struct {
+	Time  time.Time
+	Value bool
+}
+
+// TemporalValuesEntity is the simplified (temporalValues) representation
+// of the evolution in time of an Entity
+type TemporalValuesEntity struct {
+	ID                 string                       `json:"id"`   // ID of the entity used to identify the single entity
+	Type               string                       `json:"type"` // Type of the entity used for categorization
+	Scope              []string                     `json:"-"`    // Hierarchical scopes of the entity, e.g. /Italy/Tuscany
+	Properties         map[string]DatasetTimeSeries `json:"-"`    // Values in time of the Properties
+	Relationships      map[string]DatasetTimeSeries `json:"-"`    // Objects in time of the Relationships
+	GeoProperties      map[string]DatasetTimeSeries `json:"-"`    // Geometries in time of the GeoProperties
+	LanguageProperties map[string]DatasetTimeSeries `json:"-"`    // Language maps in time of the LanguageProperties
+	VocabProperties    map[string]DatasetTimeSeries `json:"-"`    // Terms in time of the VocabProperties
+	JSONProperties     map[string]DatasetTimeSeries `json:"-"`    // JSON values in time of the JsonProperties
+	ListProperties     map[string]DatasetTimeSeries `json:"-"`    // Lists of values in time of the ListProperties
+	ListRelationships  map[string]DatasetTimeSeries `json:"-"`    // Lists of objects in time of the ListRelationships
+}
+
+// AggregatedValue is the result of an aggregation method over a time period
+type AggregatedValue struct {
+	Value   any
+	StartAt time.Time
+	EndAt   time.Time
+}
+
+// Aggregates are the results of each aggregation method applied to an Attribute
+type Aggregates map[string][]AggregatedValue
+
+// DatasetAggregates are the aggregates of the instances of an Attribute by datasetId.
+// The default instance has an empty datasetId.
+type DatasetAggregates map[string]Aggregates
+
+// AggregatedTemporalEntity is the aggregated (aggregatedValues) representation
+// of the evolution in time of an Entity
+type AggregatedTemporalEntity struct {
+	ID                 string                       `json:"id"`   // ID of the entity used to identify the single entity
+	Type               string                       `json:"type"` // Type of the entity used for categorization
+	Scope              []string                     `json:"-"`    // Hierarchical scopes of the entity, e.g. /Italy/Tuscany
+	Properties         map[string]DatasetAggregates `json:"-"`    // Aggregated values of the Properties
+	Relationships      map[string]DatasetAggregates `json:"-"`    // Aggregated objects of the Relationships
+	GeoProperties      map[string]DatasetAggregates `json:"-"`    // Aggregated geometries of the GeoProperties
+	LanguageProperties map[string]DatasetAggregates `json:"-"`    // Aggregated language maps of the LanguageProperties
+	VocabProperties    map[string]DatasetAggregates `json:"-"`    // Aggregated terms of the VocabProperties
+	JSONProperties     map[string]DatasetAggregates `json:"-"`    // Aggregated JSON values of the JsonProperties
+	ListProperties     map[string]DatasetAggregates `json:"-"`    // Aggregated lists of values of the ListProperties
+	ListRelationships  map[string]DatasetAggregates `json:"-"`    // Aggregated lists of objects of the ListRelationships
+}
+
+func (p TimePoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{p.Value, p.Time.UTC().Format(TimeRFC3339Micro)})
+}
+
+func (p *TimePoint) UnmarshalJSON(b []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(b, &pair); err != nil || len(pair) != 2 {
+		return ErrTemporalEntityInvalidPoint
+	}
+
+	d := TimePoint{}
+	if err := json.Unmarshal(pair[0], &d.Value); err != nil {
+		return errors.Wrap(ErrTemporalEntityInvalidPoint, err.Error())
+	}
+	if err := json.Unmarshal(pair[1], &d.Time); err != nil {
+		return errors.Wrap(ErrTemporalEntityInvalidPoint, err.Error())
+	}
+
+	*p = d
+	return nil
+}
+
+func (v AggregatedValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{
+		v.Value,
+		v.StartAt.UTC().Format(TimeRFC3339Micro),
+		v.EndAt.UTC().Format(TimeRFC3339Micro),
+	})
+}
+
+func (v *AggregatedValue) UnmarshalJSON(b []byte) error {
+	var triple []json.RawMessage
+	if err := json.Unmarshal(b, &triple); err != nil || len(triple) != 3 {
+		return ErrTemporalEntityInvalidAggregate
+	}
+
+	d := AggregatedValue{}
+	if err := json.Unmarshal(triple[0], &d.Value); err != nil {
+		return errors.Wrap(ErrTemporalEntityInvalidAggregate, err.Error())
+	}
+	if err := json.Unmarshal(triple[1], &d.StartAt); err != nil {
+		return errors.Wrap(ErrTemporalEntityInvalidAggregate, err.Error())
+	}
+	if err := json.Unmarshal(triple[2], &d.EndAt); err != nil {
+		return errors.Wrap(ErrTemporalEntityInvalidAggregate, err.Error())
+	}
+
+	*v = d
+	return nil
+}
+
+// Float64s returns the series of numeric values,
+// ErrTemporalValueWrongType is returned if any value is not a number
+func (s TimeSeries) Float64s() ([]Float64Point, error) {
+	points := make([]Float64Point, 0, len(s))
+	for _, p := range s {
+		v, ok := p.Value.(float64)
+		if !ok {
+			return nil, errors.Wrapf(ErrTemporalValueWrongType, "value at %s is %T, not a number", p.Time.Format(TimeRFC3339Micro), p.Value)
+		}
+		points = append(points, Float64Point{Time: p.Time, Value: v})
+	}
+	return points, nil
+}
+
+// Strings returns the series of string values,
+// ErrTemporalValueWrongType is returned if any value is not a string
+func (s TimeSeries) Strings() ([]StringPoint, error) {
+	points := make([]StringPoint, 0, len(s))
+	for _, p := range s {
+		v, ok := p.Value.(string)
+		if !ok {
+			return nil, errors.Wrapf(ErrTemporalValueWrongType, "value at %s is %T, not a string", p.Time.Format(TimeRFC3339Micro), p.Value)
+		}
+		points = append(points, StringPoint{Time: p.Time, Value: v})
+	}
+	return points, nil
+}
+
+// Bools returns the series of boolean values,
+// ErrTemporalValueWrongType is returned if any value is not a boolean
+func (s TimeSeries) Bools() ([]BoolPoint, error) {
+	points := make([]BoolPoint, 0, len(s))
+	for _, p := range s {
+		v, ok := p.Value.(bool)
+		if !ok {
+			return nil, errors.Wrapf(ErrTemporalValueWrongType, "value at %s is %T, not a boolean", p.Time.Format(TimeRFC3339Micro), p.Value)
+		}
+		points = append(points, BoolPoint{Time: p.Time, Value: v})
+	}
+	return points, nil
+}
+
+// Default returns the time series of the instance without datasetId
+func (d DatasetTimeSeries) Default() TimeSeries {
+	return d[""]
+}
+
+// Default returns the aggregates of the instance without datasetId
+func (d DatasetAggregates) Default() Aggregates {
+	return d[""]
+}
+
+// Series returns the results of the aggregation method as a time series,
+// each value is timestamped with the start of its period
+func (a Aggregates) Series(method string) TimeSeries {
+	values, ok := a[method]
+	if !ok {
+		return nil
+	}
+	series := make(TimeSeries, 0, len(values))
+	for _, v := range values {
+		series = append(series, TimePoint{Time: v.StartAt, Value: v.Value})
+	}
+	return series
+}
+
+func (e *TemporalValuesEntity) UnmarshalJSON(b []byte) error {
+	// Use an alias to avoid recursion into this function
+	type readTemporalValuesEntity TemporalValuesEntity
+
+	d := readTemporalValuesEntity{}
+	if err := json.Unmarshal(b, &d); err != nil {
+		return ErrInvalidTemporalEntity(err)
+	}
+
+	scope, attributes, err := simplifiedTemporalAttributes(b, d.ID, d.Type, reflect.TypeOf(d))
+	if err != nil {
+		return err
+	}
+	d.Scope = scope
+
+	// Simplified representation holds values for Properties and GeoProperties,
+	// objects for Relationships, and a member of its own for every other kind
+	type Attribute struct {
+		Type         string     `json:"type"`
+		DatasetID    string     `json:"datasetId"`
+		Values       TimeSeries `json:"values"`
+		Objects      TimeSeries `json:"objects"`
+		LanguageMaps TimeSeries `json:"languageMaps"`
+		Vocabs       TimeSeries `json:"vocabs"`
+		Jsons        TimeSeries `json:"jsons"`
+		ValueLists   TimeSeries `json:"valueLists"`
+		ObjectsLists TimeSeries `json:"objectsLists"`
+	}
+
+	for k, instances := range attributes {
+		for _, v := range instances {
+			a := Attribute{}
+			err := json.Unmarshal(v, &a)
+			if err != nil {
+				return ErrInvalidTemporalEntity(errors.Wrapf(err, "cannot unmarshal attribute %s", k))
+			}
+
+			switch a.Type {
+			case "Property":
+				err = setDataset(&d.Properties, k, a.DatasetID, a.Values)
+			case "Relationship":
+				err = setDataset(&d.Relationships, k, a.DatasetID, a.Objects)
+			case "GeoProperty":
+				err = setDataset(&d.GeoProperties, k, a.DatasetID, a.Values)
+			case "LanguageProperty":
+				err = setDataset(&d.LanguageProperties, k, a.DatasetID, a.LanguageMaps)
+			case "VocabProperty":
+				err = setDataset(&d.VocabProperties, k, a.DatasetID, a.Vocabs)
+			case "JsonProperty":
+				err = setDataset(&d.JSONProperties, k, a.DatasetID, a.Jsons)
+			case "ListProperty":
+				err = setDataset(&d.ListProperties, k, a.DatasetID, a.ValueLists)
+			case "ListRelationship":
+				err = setDataset(&d.ListRelationships, k, a.DatasetID, a.ObjectsLists)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Assign fields to pointed structure
+	*e = TemporalValuesEntity(d)
+
+	return nil
+}
+
+func (e *AggregatedTemporalEntity) UnmarshalJSON(b []byte) error {
+	// Use an alias to avoid recursion into this function
+	type readAggregatedTemporalEntity AggregatedTemporalEntity
+
+	d := readAggregatedTemporalEntity{}
+	if err := json.Unmarshal(b, &d); err != nil {
+		return ErrInvalidTemporalEntity(err)
+	}
+
+	scope, attributes, err := simplifiedTemporalAttributes(b, d.ID, d.Type, reflect.TypeOf(d))
+	if err != nil {
+		return err
+	}
+	d.Scope = scope
+
+	type Attribute struct {
+		Type      string `json:"type"`
+		DatasetID string `json:"datasetId"`
+	}
+
+	for k, instances := range attributes {
+		for _, v := range instances {
+			a := Attribute{}
+			err := json.Unmarshal(v, &a)
+			if err != nil {
+				return ErrInvalidTemporalEntity(errors.Wrapf(err, "cannot unmarshal attribute %s", k))
+			}
+
+			// Every member but type and datasetId is an aggregation method
+			var members map[string]json.RawMessage
+			_ = json.Unmarshal(v, &members)
+			delete(members, "type")
+			delete(members, "datasetId")
+
+			aggregates := Aggregates{}
+			for method, raw := range members {
+				values := []AggregatedValue{}
+				err := json.Unmarshal(raw, &values)
+				if err != nil {
+					return ErrInvalidTemporalEntity(errors.Wrapf(err, "cannot unmarshal %s of attribute %s", method, k))
+				}
+				aggregates[method] = values
+			}
+
+			switch a.Type {
+			case "Property":
+				err = setDataset(&d.Properties, k, a.DatasetID, aggregates)
+			case "Relationship":
+				err = setDataset(&d.Relationships, k, a.DatasetID, aggregates)
+			case "GeoProperty":
+				err = setDataset(&d.GeoProperties, k, a.DatasetID, aggregates)
+			case "LanguageProperty":
+				err = setDataset(&d.LanguageProperties, k, a.DatasetID, aggregates)
+			case "VocabProperty":
+				err = setDataset(&d.VocabProperties, k, a.DatasetID, aggregates)
+			case "JsonProperty":
+				err = setDataset(&d.JSONProperties, k, a.DatasetID, aggregates)
+			case "ListProperty":
+				err = setDataset(&d.ListProperties, k, a.DatasetID, aggregates)
+			case "ListRelationship":
+				err = setDataset(&d.ListRelationships, k, a.DatasetID, aggregates)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Assign fields to pointed structure
+	*e = AggregatedTemporalEntity(d)
+
+	return nil
+}
+
+// simplifiedTemporalAttributes checks the mandatory members of a simplified or
+// aggregated temporal entity, and returns its scopes and the JSON objects of the instances of its Attributes
+func simplifiedTemporalAttributes(b []byte, id, typ string, fields reflect.Type) ([]string, map[string][]json.RawMessage, error) {
+	// Check for missing mandatory values
+	if id == "" {
+		return nil, nil, ErrEntityMissingID
+	}
+	if typ == "" {
+		return nil, nil, ErrEntityMissingType
+	}
+
+	var jsonValues map[string]json.RawMessage
+	_ = json.Unmarshal(b, &jsonValues)
+
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		jsonTag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if jsonTag != "" && jsonTag != "-" {
+			delete(jsonValues, jsonTag)
+		}
+	}
+	delete(jsonValues, "@context")
+
+	scope, err := unmarshalScope(jsonValues)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attributes := map[string][]json.RawMessage{}
+	for k, v := range jsonValues {
+		trimmed := bytes.TrimSpace(v)
+		if len(trimmed) == 0 {
+			continue
+		}
+		switch trimmed[0] {
+		case '{':
+			attributes[k] = []json.RawMessage{trimmed}
+		case '[':
+			// Several instances, one for each datasetId
+			instances := []json.RawMessage{}
+			err := json.Unmarshal(trimmed, &instances)
+			if err != nil {
+				return nil, nil, ErrInvalidTemporalEntity(errors.Wrapf(err, "cannot unmarshal attribute %s", k))
+			}
+			if len(instances) > 0 {
+				attributes[k] = instances
+			}
+		}
+		// Other members, e.g. system attributes, are not Attributes
+	}
+	return scope, attributes, nil
+}
+
+// setDataset stores the temporal representation of an instance of the Attribute named name,
+// at most one instance can have the datasetId. Sets without Attributes are left nil.
+func setDataset[M ~map[string]T, T any](attributes *map[string]M, name, datasetID string, value T) error {
+	if *attributes == nil {
+		*attributes = map[string]M{}
+	}
+	if (*attributes)[name] == nil {
+		(*attributes)[name] = M{}
+	}
+	if _, ok := (*attributes)[name][datasetID]; ok {
+		return ErrInvalidTemporalEntity(errors.Wrapf(ErrTemporalEntityDuplicateDataset, "attribute %s, datasetId %q", name, datasetID))
+	}
+	(*attributes)[name][datasetID] = value
+	return nil
+}
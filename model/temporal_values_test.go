@@ -0,0 +1,208 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalTemporalValuesEntity(t *testing.T) {
+	first := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	second := time.Date(2022, 3, 1, 11, 0, 0, 0, time.UTC)
+
+	var e model.TemporalValuesEntity
+	err := json.Unmarshal([]byte(`{
+    "id": "urn:room:1",
+    "type": "Room",
+    "temperature": {"type": "Property", "values": [[21.5, "2022-03-01T10:00:00Z"], [22, "2022-03-01T11:00:00Z"]]},
+    "wall": [{"type": "Relationship", "objects": [["urn:wall:1", "2022-03-01T10:00:00Z"]]}],
+    "modifiedAt": "2022-03-01T11:00:00Z"
+  }`), &e)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TemporalValuesEntity{
+		ID:   "urn:room:1",
+		Type: "Room",
+		Properties: map[string]model.DatasetTimeSeries{
+			"temperature": {"": {
+				{Time: first, Value: 21.5},
+				{Time: second, Value: float64(22)},
+			}},
+		},
+		Relationships: map[string]model.DatasetTimeSeries{
+			"wall": {"": {{Time: first, Value: "urn:wall:1"}}},
+		},
+	}, e)
+
+	temperature, err := e.Properties["temperature"].Default().Float64s()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Float64Point{{Time: first, Value: 21.5}, {Time: second, Value: 22}}, temperature)
+
+	_, err = e.Properties["temperature"].Default().Strings()
+	assert.ErrorIs(t, err, model.ErrTemporalValueWrongType)
+
+	walls, err := e.Relationships["wall"].Default().Strings()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.StringPoint{{Time: first, Value: "urn:wall:1"}}, walls)
+}
+
+func TestUnmarshalTemporalValuesEntityDatasets(t *testing.T) {
+	at := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	var e model.TemporalValuesEntity
+	err := json.Unmarshal([]byte(`{
+    "id": "urn:room:1",
+    "type": "Room",
+    "temperature": [
+      {"type": "Property", "values": [[21.5, "2022-03-01T10:00:00Z"]]},
+      {"type": "Property", "datasetId": "urn:dataset:sensor2", "values": [[23, "2022-03-01T10:00:00Z"]]}
+    ]
+  }`), &e)
+	assert.NoError(t, err)
+	assert.Equal(t, model.DatasetTimeSeries{
+		"":                    {{Time: at, Value: 21.5}},
+		"urn:dataset:sensor2": {{Time: at, Value: float64(23)}},
+	}, e.Properties["temperature"])
+}
+
+func TestUnmarshalTemporalValuesEntityScope(t *testing.T) {
+	var e model.TemporalValuesEntity
+	err := json.Unmarshal([]byte(`{
+    "id": "urn:room:1",
+    "type": "Room",
+    "scope": ["/Italy/Tuscany", "/Company/Sales"],
+    "temperature": {"type": "Property", "values": [[21.5, "2022-03-01T10:00:00Z"]]}
+  }`), &e)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/Italy/Tuscany", "/Company/Sales"}, e.Scope)
+	assert.Len(t, e.Properties["temperature"].Default(), 1)
+
+	var a model.AggregatedTemporalEntity
+	err = json.Unmarshal([]byte(`{
+    "id": "urn:room:1",
+    "type": "Room",
+    "scope": "/Italy/Tuscany",
+    "temperature": {"type": "Property", "avg": [[21.5, "2022-03-01T10:00:00Z", "2022-03-01T11:00:00Z"]]}
+  }`), &a)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/Italy/Tuscany"}, a.Scope)
+	assert.Len(t, a.Properties["temperature"].Default()["avg"], 1)
+}
+
+func TestUnmarshalTemporalValuesEntityKinds(t *testing.T) {
+	at := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	var e model.TemporalValuesEntity
+	err := json.Unmarshal([]byte(`{
+    "id": "urn:room:1",
+    "type": "Room",
+    "name": {"type": "LanguageProperty", "languageMaps": [[{"en": "Blue room"}, "2022-03-01T10:00:00Z"]]},
+    "readings": {"type": "ListProperty", "valueLists": [[[21.5, 22], "2022-03-01T10:00:00Z"]]},
+    "category": {"type": "VocabProperty", "vocabs": [["meeting", "2022-03-01T10:00:00Z"]]},
+    "config": {"type": "JsonProperty", "jsons": [[{"mode": "eco"}, "2022-03-01T10:00:00Z"]]},
+    "sensors": {"type": "ListRelationship", "objectsLists": [[["urn:sensor:1"], "2022-03-01T10:00:00Z"]]}
+  }`), &e)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TimeSeries{{Time: at, Value: map[string]any{"en": "Blue room"}}}, e.LanguageProperties["name"].Default())
+	assert.Equal(t, model.TimeSeries{{Time: at, Value: []any{21.5, float64(22)}}}, e.ListProperties["readings"].Default())
+	assert.Equal(t, model.TimeSeries{{Time: at, Value: "meeting"}}, e.VocabProperties["category"].Default())
+	assert.Equal(t, model.TimeSeries{{Time: at, Value: map[string]any{"mode": "eco"}}}, e.JSONProperties["config"].Default())
+	assert.Equal(t, model.TimeSeries{{Time: at, Value: []any{"urn:sensor:1"}}}, e.ListRelationships["sensors"].Default())
+	assert.Nil(t, e.Properties)
+
+	var a model.AggregatedTemporalEntity
+	err = json.Unmarshal([]byte(`{
+    "id": "urn:room:1",
+    "type": "Room",
+    "name": {"type": "LanguageProperty", "totalCount": [[2, "2022-03-01T10:00:00Z", "2022-03-01T11:00:00Z"]]},
+    "readings": {"type": "ListProperty", "totalCount": [[3, "2022-03-01T10:00:00Z", "2022-03-01T11:00:00Z"]]}
+  }`), &a)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TimeSeries{{Time: at, Value: float64(2)}}, a.LanguageProperties["name"].Default().Series(model.AggrTotalCount))
+	assert.Equal(t, model.TimeSeries{{Time: at, Value: float64(3)}}, a.ListProperties["readings"].Default().Series(model.AggrTotalCount))
+	assert.Nil(t, a.Properties)
+}
+
+func TestUnmarshalTemporalValuesEntityErrors(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		err   error
+	}{
+		"missing id": {
+			input: `{"type": "Room"}`,
+			err:   model.ErrEntityMissingID,
+		},
+		"invalid pair": {
+			input: `{"id": "urn:room:1", "type": "Room", "temperature": {"type": "Property", "values": [[21.5]]}}`,
+			err:   model.ErrTemporalEntityInvalidPoint,
+		},
+		"invalid time": {
+			input: `{"id": "urn:room:1", "type": "Room", "temperature": {"type": "Property", "values": [[21.5, "yesterday"]]}}`,
+			err:   model.ErrTemporalEntityInvalidPoint,
+		},
+		"duplicate datasetId": {
+			input: `{"id": "urn:room:1", "type": "Room", "temperature": [
+        {"type": "Property", "datasetId": "urn:dataset:1", "values": []},
+        {"type": "Property", "datasetId": "urn:dataset:1", "values": []}
+      ]}`,
+			err: model.ErrTemporalEntityDuplicateDataset,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var e model.TemporalValuesEntity
+			err := json.Unmarshal([]byte(tc.input), &e)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestUnmarshalAggregatedTemporalEntity(t *testing.T) {
+	day := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	nextDay := day.Add(24 * time.Hour)
+
+	var e model.AggregatedTemporalEntity
+	err := json.Unmarshal([]byte(`{
+    "id": "urn:room:1",
+    "type": "Room",
+    "temperature": {
+      "type": "Property",
+      "avg": [[21.5, "2022-03-01T00:00:00Z", "2022-03-02T00:00:00Z"], [20, "2022-03-02T00:00:00Z", "2022-03-03T00:00:00Z"]],
+      "max": [[25, "2022-03-01T00:00:00Z", "2022-03-02T00:00:00Z"], [23, "2022-03-02T00:00:00Z", "2022-03-03T00:00:00Z"]]
+    },
+    "wall": {
+      "type": "Relationship",
+      "distinctCount": [[1, "2022-03-01T00:00:00Z", "2022-03-03T00:00:00Z"]]
+    }
+  }`), &e)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.AggregatedValue{
+		{Value: 21.5, StartAt: day, EndAt: nextDay},
+		{Value: float64(20), StartAt: nextDay, EndAt: nextDay.Add(24 * time.Hour)},
+	}, e.Properties["temperature"].Default()[model.AggrAvg])
+	assert.Len(t, e.Relationships["wall"].Default()[model.AggrDistinctCount], 1)
+
+	maxTemperature, err := e.Properties["temperature"].Default().Series(model.AggrMax).Float64s()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Float64Point{{Time: day, Value: 25}, {Time: nextDay, Value: 23}}, maxTemperature)
+	assert.Nil(t, e.Properties["temperature"].Default().Series(model.AggrMin))
+}
+
+func TestUnmarshalAggregatedTemporalEntityErrors(t *testing.T) {
+	var e model.AggregatedTemporalEntity
+	err := json.Unmarshal([]byte(`{"id": "urn:room:1", "type": "Room", "temperature": {
+    "type": "Property", "avg": [[21.5, "2022-03-01T00:00:00Z"]]
+  }}`), &e)
+	assert.ErrorIs(t, err, model.ErrTemporalEntityInvalidAggregate)
+}
+
+func TestMarshalTimePoint(t *testing.T) {
+	b, err := json.Marshal(model.TimeSeries{
+		{Time: time.Date(2022, 3, 1, 10, 0, 0, 123456789, time.UTC), Value: 21.5},
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[[21.5, "2022-03-01T10:00:00.123456Z"]]`, string(b))
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/philiphil/geojson"
 	"github.com/phoops/ngsi-gold/model"
 	"github.com/stretchr/testify/assert"
 )
@@ -30,7 +31,7 @@ func TestUnmarshalRelationship(t *testing.T) {
 			relationship: model.Relationship{
 				Object: "urn:object_id",
 				Relationships: model.Relationships{
-					"r1": model.Relationship{Object: "urn:nested_object_id"},
+					"r1": {{Object: "urn:nested_object_id"}},
 				},
 			},
 		},
@@ -40,7 +41,7 @@ func TestUnmarshalRelationship(t *testing.T) {
 			relationship: model.Relationship{
 				Object: "urn:object_id",
 				Properties: model.Properties{
-					"p1": model.Property{Value: "property value"},
+					"p1": {{Value: "property value"}},
 				},
 			},
 		},
@@ -151,6 +152,7 @@ func TestUnmarshalProperty(t *testing.T) {
 	}
 
 	testDataset := "UV index"
+	testInstance := "urn:instance:1"
 	tests := []testCase{
 		{
 			name: "shallow",
@@ -165,7 +167,7 @@ func TestUnmarshalProperty(t *testing.T) {
 			property: model.Property{
 				Value: "words or phrases",
 				Relationships: model.Relationships{
-					"r1": model.Relationship{Object: "urn:nested_object_id"},
+					"r1": {{Object: "urn:nested_object_id"}},
 				},
 			},
 		},
@@ -175,7 +177,7 @@ func TestUnmarshalProperty(t *testing.T) {
 			property: model.Property{
 				Value: "words or phrases",
 				Properties: model.Properties{
-					"p1": model.Property{Value: "property value"},
+					"p1": {{Value: "property value"}},
 				},
 			},
 		},
@@ -225,6 +227,14 @@ func TestUnmarshalProperty(t *testing.T) {
 				DatasetID: &testDataset,
 			},
 		},
+		{
+			name: "set instanceId",
+			json: `{"type":"Property","value":9.4,"instanceId":"urn:instance:1"}`,
+			property: model.Property{
+				Value:      float64(9.4),
+				InstanceID: &testInstance,
+			},
+		},
 	}
 
 	for _, y := range tests {
@@ -333,7 +343,7 @@ func TestUnmarshalEntity(t *testing.T) {
 				ID:   "urn:room:1",
 				Type: "Room",
 				Relationships: model.Relationships{
-					"r1": model.Relationship{Object: "urn:nested_object_id"},
+					"r1": {{Object: "urn:nested_object_id"}},
 				},
 			},
 		},
@@ -344,7 +354,28 @@ func TestUnmarshalEntity(t *testing.T) {
 				ID:   "urn:room:1",
 				Type: "Room",
 				Properties: model.Properties{
-					"p1": model.Property{Value: "property value"},
+					"p1": {{Value: "property value"}},
+				},
+			},
+		},
+		{
+			name: "nested geoproperty",
+			json: `{"type":"Room", "id":"urn:room:1",
+				"destination":{"type":"GeoProperty","value":{"type":"Point","coordinates":[11.25,43.77]}},
+				"p1":{"type":"Property","value":1,"origin":{"type":"GeoProperty","value":{"type":"Point","coordinates":[11,43]}}}}`,
+			entity: model.Entity{
+				ID:   "urn:room:1",
+				Type: "Room",
+				GeoProperties: model.GeoProperties{
+					"destination": {{Value: geojson.NewPointGeometry([]float64{11.25, 43.77})}},
+				},
+				Properties: model.Properties{
+					"p1": {{
+						Value: 1.0,
+						GeoProperties: model.GeoProperties{
+							"origin": {{Value: geojson.NewPointGeometry([]float64{11, 43})}},
+						},
+					}},
 				},
 			},
 		},
@@ -418,6 +449,11 @@ func TestUnmarshalEntityErrors(t *testing.T) {
 			json:   `{"type":"Room", "id":"urn:room:1","p1":{"type":"Property"}}`,
 			errMsg: "cannot unmarshal property p1",
 		},
+		{
+			name:   "invalid nested geoproperty",
+			json:   `{"type":"Room", "id":"urn:room:1","g1":{"type":"GeoProperty","value":{"type":"GeometryCollection","geometries":[]}}}`,
+			errMsg: "cannot unmarshal geoproperty g1",
+		},
 	}
 
 	for _, y := range tests {
@@ -439,3 +475,42 @@ func TestUnmarshalEntityErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalEntityLocationInstances(t *testing.T) {
+	// A single instance in an array is the location
+	e := model.Entity{}
+	err := json.Unmarshal([]byte(`{
+		"id": "urn:ngsi-ld:Vehicle:1",
+		"type": "Vehicle",
+		"location": [{"type": "GeoProperty", "value": {"type": "Point", "coordinates": [11.25, 43.77]}}]
+	}`), &e)
+	assert.NoError(t, err)
+	assert.NotNil(t, e.Location)
+	assert.Nil(t, e.GeoProperties)
+
+	// Several instances are kept with the other GeoProperties
+	const vehicle = `{
+		"id": "urn:ngsi-ld:Vehicle:1",
+		"type": "Vehicle",
+		"location": [
+			{"type": "GeoProperty", "value": {"type": "Point", "coordinates": [11.25, 43.77]}},
+			{"type": "GeoProperty", "value": {"type": "Point", "coordinates": [11.26, 43.78]}, "datasetId": "urn:ngsi-ld:Dataset:gps"}
+		]
+	}`
+	e = model.Entity{}
+	err = json.Unmarshal([]byte(vehicle), &e)
+	assert.NoError(t, err)
+	assert.Nil(t, e.Location)
+	assert.NoError(t, e.Validate(true))
+	gps, ok := e.GeoProperties["location"].ByDataset("urn:ngsi-ld:Dataset:gps")
+	assert.True(t, ok)
+	assert.Equal(t, geojson.NewPointGeometry([]float64{11.26, 43.78}), gps.Value)
+
+	b, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.JSONEq(t, vehicle, string(b))
+
+	// The same GeoProperty can't be in both places
+	e.Location = &model.GeoProperty{Value: geojson.NewPointGeometry([]float64{11.25, 43.77})}
+	assert.ErrorIs(t, e.Validate(true), model.ErrEntityTermCollision)
+}
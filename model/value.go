@@ -0,0 +1,197 @@
+package model
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// dateTimeType is the @type of the structured values holding a timestamp
+const dateTimeType = "DateTime"
+
+// Float64 returns the value of the Property as a number
+func (p *Property) Float64() (float64, error) {
+	switch v := p.Value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, errors.Wrap(ErrPropertyValueWrongType, err.Error())
+		}
+		return f, nil
+	}
+
+	rv := reflect.ValueOf(p.Value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	}
+	return 0, wrongValueType(p.Value, "a number")
+}
+
+// Int64 returns the value of the Property as an integer.
+// Numbers with a fractional part, or out of the int64 range, are refused.
+func (p *Property) Int64() (int64, error) {
+	switch v := p.Value.(type) {
+	case json.Number:
+		i, err := v.Int64()
+		if err != nil {
+			return 0, errors.Wrap(ErrPropertyValueWrongType, err.Error())
+		}
+		return i, nil
+	case float64, float32:
+		f, _ := p.Float64()
+		if f != math.Trunc(f) {
+			return 0, errors.Wrapf(ErrPropertyValueWrongType, "value %v is not an integer", f)
+		}
+		// 2^63 is the first float64 out of range
+		if f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, errors.Wrapf(ErrPropertyValueWrongType, "value %v overflows int64", f)
+		}
+		return int64(f), nil
+	}
+
+	rv := reflect.ValueOf(p.Value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, errors.Wrapf(ErrPropertyValueWrongType, "value %d overflows int64", rv.Uint())
+		}
+		return int64(rv.Uint()), nil
+	}
+	return 0, wrongValueType(p.Value, "an integer")
+}
+
+// String returns the value of the Property as a string
+func (p *Property) String() (string, error) {
+	v, ok := p.Value.(string)
+	if !ok {
+		return "", wrongValueType(p.Value, "a string")
+	}
+	return v, nil
+}
+
+// Bool returns the value of the Property as a boolean
+func (p *Property) Bool() (bool, error) {
+	v, ok := p.Value.(bool)
+	if !ok {
+		return false, wrongValueType(p.Value, "a boolean")
+	}
+	return v, nil
+}
+
+// Time returns the value of the Property as a timestamp,
+// the value must be a DateTime, i.e. {"@type": "DateTime", "@value": "..."}
+func (p *Property) Time() (time.Time, error) {
+	switch v := p.Value.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		if v != nil {
+			return *v, nil
+		}
+	case map[string]any:
+		if v["@type"] != dateTimeType {
+			return time.Time{}, errors.Wrapf(ErrPropertyValueWrongType, "value has @type %v, not %s", v["@type"], dateTimeType)
+		}
+		t, err := parseDateTime(v["@value"])
+		if err != nil {
+			return time.Time{}, errors.Wrap(ErrPropertyValueWrongType, err.Error())
+		}
+		return t, nil
+	}
+	return time.Time{}, wrongValueType(p.Value, "a DateTime")
+}
+
+// Object decodes the structured value of the Property into the value pointed by into,
+// as encoding/json would do
+func (p *Property) Object(into any) error {
+	rv := reflect.Indirect(reflect.ValueOf(p.Value))
+	if rv.Kind() != reflect.Map && rv.Kind() != reflect.Struct {
+		return wrongValueType(p.Value, "an object")
+	}
+
+	b, err := json.Marshal(p.Value)
+	if err != nil {
+		return errors.Wrap(ErrPropertyValueWrongType, err.Error())
+	}
+	err = json.Unmarshal(b, into)
+	if err != nil {
+		return errors.Wrap(ErrPropertyValueWrongType, err.Error())
+	}
+	return nil
+}
+
+// Array returns the items of the value of the Property
+func (p *Property) Array() ([]any, error) {
+	if v, ok := p.Value.([]any); ok {
+		return v, nil
+	}
+
+	rv := reflect.ValueOf(p.Value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, wrongValueType(p.Value, "an array")
+	}
+	items := make([]any, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		items = append(items, rv.Index(i).Interface())
+	}
+	return items, nil
+}
+
+func wrongValueType(value any, expected string) error {
+	if value == nil {
+		return errors.Wrapf(ErrPropertyValueWrongType, "value is null, not %s", expected)
+	}
+	return errors.Wrapf(ErrPropertyValueWrongType, "value is %T, not %s", value, expected)
+}
+
+func parseDateTime(value any) (time.Time, error) {
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, errors.Errorf("@value of DateTime is %T, not a string", value)
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid DateTime")
+	}
+	return t, nil
+}
+
+// decodeStructuredValue returns the Go value of a structured value, e.g. a time.Time for DateTime.
+// Other values are returned unchanged.
+func decodeStructuredValue(value any) any {
+	v, ok := value.(map[string]any)
+	if !ok || len(v) != 2 || v["@type"] != dateTimeType {
+		return value
+	}
+	t, err := parseDateTime(v["@value"])
+	if err != nil {
+		return value
+	}
+	return t
+}
+
+// encodeStructuredValue returns the JSON-LD representation of the Go value,
+// e.g. a DateTime for a time.Time. Other values are returned unchanged.
+func encodeStructuredValue(value any) any {
+	switch v := value.(type) {
+	case time.Time:
+		return map[string]any{"@type": dateTimeType, "@value": v.UTC().Format(TimeRFC3339Micro)}
+	case *time.Time:
+		if v != nil {
+			return map[string]any{"@type": dateTimeType, "@value": v.UTC().Format(TimeRFC3339Micro)}
+		}
+	}
+	return value
+}
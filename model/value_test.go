@@ -0,0 +1,116 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertyTypedValues(t *testing.T) {
+	p := model.Property{}
+	err := json.Unmarshal([]byte(`{"type": "Property", "value": 21}`), &p)
+	assert.NoError(t, err)
+
+	f, err := p.Float64()
+	assert.NoError(t, err)
+	assert.Equal(t, 21.0, f)
+	i, err := p.Int64()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21), i)
+	_, err = p.String()
+	assert.ErrorIs(t, err, model.ErrPropertyValueWrongType)
+	assert.ErrorContains(t, err, "value is float64, not a string")
+	_, err = p.Bool()
+	assert.ErrorIs(t, err, model.ErrPropertyValueWrongType)
+
+	p.Value = 21.5
+	_, err = p.Int64()
+	assert.ErrorIs(t, err, model.ErrPropertyValueWrongType)
+	assert.ErrorContains(t, err, "not an integer")
+
+	p.Value = 1e19
+	_, err = p.Int64()
+	assert.ErrorContains(t, err, "overflows int64")
+
+	p.Value = uint8(7)
+	f, err = p.Float64()
+	assert.NoError(t, err)
+	assert.Equal(t, 7.0, f)
+
+	p.Value = "kitchen"
+	s, err := p.String()
+	assert.NoError(t, err)
+	assert.Equal(t, "kitchen", s)
+	_, err = p.Float64()
+	assert.ErrorContains(t, err, "value is string, not a number")
+
+	p.Value = true
+	b, err := p.Bool()
+	assert.NoError(t, err)
+	assert.True(t, b)
+}
+
+func TestPropertyStructuredValues(t *testing.T) {
+	p := model.Property{}
+	err := json.Unmarshal([]byte(`{"type": "Property", "value": {"width": 4, "length": 5}}`), &p)
+	assert.NoError(t, err)
+
+	size := struct {
+		Width  int `json:"width"`
+		Length int `json:"length"`
+	}{}
+	assert.NoError(t, p.Object(&size))
+	assert.Equal(t, 4, size.Width)
+	assert.Equal(t, 5, size.Length)
+	_, err = p.Array()
+	assert.ErrorContains(t, err, "value is map[string]interface {}, not an array")
+	_, err = p.Time()
+	assert.ErrorIs(t, err, model.ErrPropertyValueWrongType)
+
+	p.Value = []string{"kitchen", "north"}
+	items, err := p.Array()
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"kitchen", "north"}, items)
+	assert.ErrorIs(t, p.Object(&size), model.ErrPropertyValueWrongType)
+}
+
+func TestPropertyDateTime(t *testing.T) {
+	p := model.Property{}
+	err := json.Unmarshal([]byte(`{
+		"type": "Property",
+		"value": {"@type": "DateTime", "@value": "2022-03-01T10:00:00.5Z"}
+	}`), &p)
+	assert.NoError(t, err)
+
+	expected := time.Date(2022, 3, 1, 10, 0, 0, 500000000, time.UTC)
+	assert.Equal(t, expected, p.Value)
+	v, err := p.Time()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, v)
+
+	// DateTimes are encoded back as structured values
+	b, err := json.Marshal(p)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "Property",
+		"value": {"@type": "DateTime", "@value": "2022-03-01T10:00:00.5Z"}
+	}`, string(b))
+
+	// Other structured values are left untouched
+	err = json.Unmarshal([]byte(`{
+		"type": "Property",
+		"value": {"@type": "Date", "@value": "2022-03-01"}
+	}`), &p)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"@type": "Date", "@value": "2022-03-01"}, p.Value)
+	_, err = p.Time()
+	assert.ErrorContains(t, err, "value has @type Date, not DateTime")
+
+	p.Value = map[string]any{"@type": "DateTime", "@value": "yesterday"}
+	_, err = p.Time()
+	assert.ErrorIs(t, err, model.ErrPropertyValueWrongType)
+	assert.ErrorContains(t, err, "invalid DateTime")
+}
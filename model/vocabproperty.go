@@ -0,0 +1,120 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// VocabProperty is an Attribute that holds terms of the @context, expanded to IRIs
+// by the Context Broker. A single term is serialized as a string.
+type VocabProperty struct {
+	Vocab              []string           `json:"-"`
+	Properties         Properties         `json:"-"`
+	Relationships      Relationships      `json:"-"`
+	GeoProperties      GeoProperties      `json:"-"`
+	LanguageProperties LanguageProperties `json:"-"`
+	VocabProperties    VocabProperties    `json:"-"`
+	JSONProperties     JSONProperties     `json:"-"`
+	ListProperties     ListProperties     `json:"-"`
+	ListRelationships  ListRelationships  `json:"-"`
+	ObservedAt         *time.Time         `json:"observedAt,omitempty"`
+	DatasetID          *string            `json:"datasetId,omitempty"`
+	InstanceID         *string            `json:"instanceId,omitempty"`
+	CreatedAt          *time.Time         `json:"createdAt,omitempty"`
+	ModifiedAt         *time.Time         `json:"modifiedAt,omitempty"`
+	DeletedAt          *time.Time         `json:"deletedAt,omitempty"`
+}
+
+func (p *VocabProperty) Type() string {
+	return "VocabProperty"
+}
+
+func (p VocabProperty) MarshalJSON() ([]byte, error) {
+	data := map[string]any{}
+
+	data["type"] = p.Type()
+	if len(p.Vocab) == 1 {
+		data["vocab"] = p.Vocab[0]
+	} else {
+		data["vocab"] = p.Vocab
+	}
+	p.members().marshal(data)
+
+	return json.Marshal(data)
+}
+
+func (p *VocabProperty) UnmarshalJSON(b []byte) error {
+	// Use an alias to avoid recursion into this function
+	type readVocabProperty VocabProperty
+
+	d := readVocabProperty{}
+	if err := json.Unmarshal(b, &d); err != nil {
+		return ErrInvalidVocabProperty(err)
+	}
+
+	// The vocab is a term or a list of terms
+	vocab := struct {
+		Vocab json.RawMessage `json:"vocab"`
+	}{}
+	_ = json.Unmarshal(b, &vocab)
+	trimmed := bytes.TrimSpace(vocab.Vocab)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return ErrVocabPropertyMissingVocab
+	}
+	if trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &d.Vocab); err != nil {
+			return ErrVocabPropertyInvalidVocab
+		}
+	} else {
+		term := ""
+		if err := json.Unmarshal(trimmed, &term); err != nil {
+			return ErrVocabPropertyInvalidVocab
+		}
+		d.Vocab = []string{term}
+	}
+
+	err := unmarshalSubAttributes(b, "VocabProperty", ErrVocabPropertyWrongType, reflect.TypeOf(d), (*VocabProperty)(&d).members().subAttributes, "vocab")
+	if err != nil {
+		return ErrInvalidVocabProperty(err)
+	}
+
+	// Assign fields to pointed structure
+	*p = VocabProperty(d)
+
+	return nil
+}
+
+func (p *VocabProperty) Validate(strictness bool) ValidationResult {
+	if len(p.Vocab) == 0 {
+		return ErrVocabPropertyMissingVocab
+	}
+	for _, term := range p.Vocab {
+		if term == "" {
+			return ErrVocabPropertyInvalidVocab
+		}
+	}
+	return p.members().validate(strictness)
+}
+
+func (p *VocabProperty) members() attributeMembers {
+	return attributeMembers{
+		observedAt: p.ObservedAt,
+		datasetID:  p.DatasetID,
+		instanceID: p.InstanceID,
+		createdAt:  p.CreatedAt,
+		modifiedAt: p.ModifiedAt,
+		deletedAt:  p.DeletedAt,
+		subAttributes: attributeSet{
+			properties:         &p.Properties,
+			relationships:      &p.Relationships,
+			geoProperties:      &p.GeoProperties,
+			languageProperties: &p.LanguageProperties,
+			vocabProperties:    &p.VocabProperties,
+			jsonProperties:     &p.JSONProperties,
+			listProperties:     &p.ListProperties,
+			listRelationships:  &p.ListRelationships,
+		},
+	}
+}
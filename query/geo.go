@@ -0,0 +1,23 @@
+package query
+
+import "strconv"
+
+// Geospatial relationships between the queried GeoProperty and the reference geometry
+const (
+	Within     = "within"
+	Contains   = "contains"
+	Intersects = "intersects"
+	Equals     = "equals"
+	Disjoint   = "disjoint"
+	Overlaps   = "overlaps"
+)
+
+// NearMaxDistance matches geometries at most meters away from the reference geometry
+func NearMaxDistance(meters float64) string {
+	return "near;maxDistance==" + strconv.FormatFloat(meters, 'f', -1, 64)
+}
+
+// NearMinDistance matches geometries at least meters away from the reference geometry
+func NearMinDistance(meters float64) string {
+	return "near;minDistance==" + strconv.FormatFloat(meters, 'f', -1, 64)
+}
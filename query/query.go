@@ -0,0 +1,231 @@
+// Package query builds expressions of the NGSI-LD Query Language,
+// as used by the q, scopeQ and csf parameters of entity queries and subscriptions.
+// https://www.etsi.org/deliver/etsi_gs/CIM/001_099/009/01.06.01_60/gs_cim009v010601p.pdf (4.9)
+package query
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phoops/ngsi-gold/model"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidValue is reported by Validate for the compared values that the query
+// language can't represent, e.g. slices, maps or structs
+var ErrInvalidValue = errors.New("query values must be strings, numbers, booleans, times or nil")
+
+// Term is a piece of a NGSI-LD query, rendered by String in the query language
+type Term interface {
+	String() string
+}
+
+// Raw is an already formatted query expression, used as is
+type Raw string
+
+func (r Raw) String() string {
+	return string(r)
+}
+
+// Path identifies the target of a query: an Attribute, one of its sub-attributes
+// or a member of a structured value
+type Path struct {
+	attrs   []string
+	members []string
+}
+
+// Attr targets an Attribute, or one of its sub-attributes when more names are given
+// e.g. Attr("temperature", "accuracy") renders as temperature.accuracy
+func Attr(name string, subAttrs ...string) Path {
+	return Path{
+		attrs: append([]string{name}, subAttrs...),
+	}
+}
+
+// Member targets a member of the structured value of the Attribute
+// e.g. Attr("address").Member("city") renders as address[city]
+func (p Path) Member(members ...string) Path {
+	return Path{
+		attrs:   p.attrs,
+		members: append(append([]string{}, p.members...), members...),
+	}
+}
+
+func (p Path) String() string {
+	s := strings.Join(p.attrs, ".")
+	if len(p.members) > 0 {
+		s += "[" + strings.Join(p.members, ".") + "]"
+	}
+	return s
+}
+
+// Eq matches values equal to v
+func (p Path) Eq(v any) Term {
+	return p.compare("==", "", v)
+}
+
+// Ne matches values different from v
+func (p Path) Ne(v any) Term {
+	return p.compare("!=", "", v)
+}
+
+// Gt matches values greater than v
+func (p Path) Gt(v any) Term {
+	return p.compare(">", "", v)
+}
+
+// Ge matches values greater or equal than v
+func (p Path) Ge(v any) Term {
+	return p.compare(">=", "", v)
+}
+
+// Lt matches values less than v
+func (p Path) Lt(v any) Term {
+	return p.compare("<", "", v)
+}
+
+// Le matches values less or equal than v
+func (p Path) Le(v any) Term {
+	return p.compare("<=", "", v)
+}
+
+// Between matches values in the closed range [lower, upper]
+func (p Path) Between(lower, upper any) Term {
+	return p.compare("==", "..", lower, upper)
+}
+
+// In matches values equal to any of the given ones, at least one is required
+func (p Path) In(value any, others ...any) Term {
+	return p.compare("==", ",", append([]any{value}, others...)...)
+}
+
+// Matches matches string values against a regular expression
+func (p Path) Matches(pattern string) Term {
+	return comparison{path: p, op: "~=", value: pattern}
+}
+
+// NotMatches matches string values not matching a regular expression
+func (p Path) NotMatches(pattern string) Term {
+	return comparison{path: p, op: "!~=", value: pattern}
+}
+
+// Exists matches entities having the Attribute
+func (p Path) Exists() Term {
+	return Raw(p.String())
+}
+
+// NotExists matches entities lacking the Attribute
+func (p Path) NotExists() Term {
+	return Raw("!" + p.String())
+}
+
+type comparison struct {
+	path  Path
+	op    string
+	value string
+	err   error // First value that can't be formatted
+}
+
+// compare builds the comparison of the path with the values, joined by sep
+func (p Path) compare(op, sep string, values ...any) comparison {
+	c := comparison{path: p, op: op}
+	formatted := make([]string, 0, len(values))
+	for _, v := range values {
+		s, err := formatValue(v)
+		if err != nil && c.err == nil {
+			c.err = err
+		}
+		formatted = append(formatted, s)
+	}
+	c.value = strings.Join(formatted, sep)
+	return c
+}
+
+func (c comparison) String() string {
+	return c.path.String() + c.op + c.value
+}
+
+// And matches when all the terms match
+func And(terms ...Term) Term {
+	return logical{op: ";", terms: terms}
+}
+
+// Or matches when any of the terms match
+func Or(terms ...Term) Term {
+	return logical{op: "|", terms: terms}
+}
+
+type logical struct {
+	op    string
+	terms []Term
+}
+
+func (l logical) String() string {
+	parts := make([]string, 0, len(l.terms))
+	for _, t := range l.terms {
+		s := t.String()
+		// Nested expressions of the other operator need explicit precedence
+		if nested, ok := t.(logical); ok && nested.op != l.op && len(nested.terms) > 1 {
+			s = "(" + s + ")"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, l.op)
+}
+
+// quoteEscaper escapes the characters ending a quoted string of the query language
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// formatValue renders a Go value as a comparable value of the query language.
+// Quotes in strings are escaped, nil values render as null.
+// ErrInvalidValue is returned for values other than strings, numbers, booleans and times.
+func formatValue(v any) (string, error) {
+	switch x := v.(type) {
+	case nil:
+		return "null", nil
+	case time.Time:
+		return x.UTC().Format(model.TimeRFC3339Micro), nil
+	case *time.Time:
+		if x == nil {
+			return "null", nil
+		}
+		return x.UTC().Format(model.TimeRFC3339Micro), nil
+	}
+
+	// Types defined on the scalar ones are accepted as well
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return `"` + quoteEscaper.Replace(rv.String()) + `"`, nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	default:
+		return "", errors.Wrapf(ErrInvalidValue, "value of type %T", v)
+	}
+}
+
+// Validate checks the values compared by the term, and by the terms it combines.
+// ErrInvalidValue is returned if any of them can't be represented in the query language.
+func Validate(t Term) error {
+	switch x := t.(type) {
+	case comparison:
+		return x.err
+	case logical:
+		for _, term := range x.terms {
+			if err := Validate(term); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
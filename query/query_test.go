@@ -0,0 +1,182 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsi-gold/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryTerms(t *testing.T) {
+	type testCase struct {
+		name  string
+		term  query.Term
+		query string
+	}
+
+	observed, err := time.Parse(time.RFC3339, "2023-02-13T11:30:40Z")
+	assert.NoError(t, err)
+
+	tests := []testCase{
+		{
+			name:  "equal number",
+			term:  query.Attr("temperature").Eq(20),
+			query: "temperature==20",
+		},
+		{
+			name:  "not equal string",
+			term:  query.Attr("status").Ne("off"),
+			query: `status!="off"`,
+		},
+		{
+			name:  "greater float",
+			term:  query.Attr("humidity").Gt(45.5),
+			query: "humidity>45.5",
+		},
+		{
+			name:  "less or equal datetime",
+			term:  query.Attr("observedAt").Le(observed),
+			query: "observedAt<=2023-02-13T11:30:40Z",
+		},
+		{
+			name:  "range",
+			term:  query.Attr("temperature").Between(10, 20),
+			query: "temperature==10..20",
+		},
+		{
+			name:  "list",
+			term:  query.Attr("color").In("red", "green"),
+			query: `color=="red","green"`,
+		},
+		{
+			name:  "pattern",
+			term:  query.Attr("name").Matches("^Room.*"),
+			query: "name~=^Room.*",
+		},
+		{
+			name:  "not pattern",
+			term:  query.Attr("name").NotMatches("^Room.*"),
+			query: "name!~=^Room.*",
+		},
+		{
+			name:  "sub-attribute",
+			term:  query.Attr("temperature", "accuracy").Lt(0.5),
+			query: "temperature.accuracy<0.5",
+		},
+		{
+			name:  "structured value member",
+			term:  query.Attr("address").Member("city").Eq("Florence"),
+			query: `address[city]=="Florence"`,
+		},
+		{
+			name:  "existence",
+			term:  query.And(query.Attr("temperature").Exists(), query.Attr("humidity").NotExists()),
+			query: "temperature;!humidity",
+		},
+		{
+			name: "and of or",
+			term: query.And(
+				query.Attr("temperature").Ge(20),
+				query.Or(query.Attr("status").Eq("on"), query.Attr("status").Eq("idle")),
+			),
+			query: `temperature>=20;(status=="on"|status=="idle")`,
+		},
+		{
+			name: "or of and",
+			term: query.Or(
+				query.And(query.Attr("a").Eq(1), query.Attr("b").Eq(2)),
+				query.Attr("c").Eq(true),
+			),
+			query: "(a==1;b==2)|c==true",
+		},
+		{
+			name:  "quoted string",
+			term:  query.Attr("name").Eq(`a"b\c`),
+			query: `name=="a\"b\\c"`,
+		},
+		{
+			name:  "nil datetime",
+			term:  query.Attr("observedAt").Eq((*time.Time)(nil)),
+			query: "observedAt==null",
+		},
+		{
+			name:  "single item list",
+			term:  query.Attr("color").In("red"),
+			query: `color=="red"`,
+		},
+		{
+			name:  "raw",
+			term:  query.And(query.Raw("a==1"), query.Attr("b").Eq(2)),
+			query: "a==1;b==2",
+		},
+	}
+
+	for _, y := range tests {
+		t.Run(y.name, func(t *testing.T) {
+			assert.Equal(t, y.query, y.term.String())
+			assert.NoError(t, query.Validate(y.term))
+		})
+	}
+}
+
+func TestValidateQueryTerms(t *testing.T) {
+	type color string
+
+	tests := map[string]struct {
+		term  query.Term
+		query string
+		err   error
+	}{
+		"unsigned": {
+			term:  query.Attr("floor").Eq(uint8(3)),
+			query: "floor==3",
+		},
+		"defined string type": {
+			term:  query.Attr("color").In(color("red"), color("green")),
+			query: `color=="red","green"`,
+		},
+		"slice": {
+			term: query.Attr("color").In([]string{"red", "green"}),
+			err:  query.ErrInvalidValue,
+		},
+		"map": {
+			term: query.Attr("address").Eq(map[string]string{"city": "Florence"}),
+			err:  query.ErrInvalidValue,
+		},
+		"struct in range": {
+			term: query.Attr("temperature").Between(10, struct{ Max int }{20}),
+			err:  query.ErrInvalidValue,
+		},
+		"nested": {
+			term: query.And(query.Attr("a").Eq(1), query.Or(query.Attr("b").Eq(2), query.Attr("c").Eq([]int{3}))),
+			err:  query.ErrInvalidValue,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := query.Validate(tc.term)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.query, tc.term.String())
+		})
+	}
+}
+
+func TestGeorel(t *testing.T) {
+	assert.Equal(t, "near;maxDistance==2000", query.NearMaxDistance(2000))
+	assert.Equal(t, "near;minDistance==12.5", query.NearMinDistance(12.5))
+}
+
+func TestScope(t *testing.T) {
+	assert.Equal(t, "/Italy/Tuscany/Florence", query.Scope("Italy", "Tuscany", "Florence").String())
+	assert.Equal(t, "/Italy/+/Florence", query.Scope("Italy", query.AnyLevel, "Florence").String())
+	assert.Equal(t, "/Italy/#", query.Scope("Italy").Descendants().String())
+	assert.Equal(t, "/#", query.AnyScope().String())
+	assert.Equal(t, "/Italy/Tuscany;/Italy/Lazio", query.And(query.Scope("Italy", "Tuscany"), query.Scope("Italy", "Lazio")).String())
+	assert.Equal(t, "/Italy/#|/France/#", query.Or(query.Scope("Italy").Descendants(), query.Scope("France").Descendants()).String())
+}
@@ -0,0 +1,39 @@
+package query
+
+import "strings"
+
+// AnyLevel matches a single level of a scope, e.g. Scope("Italy", AnyLevel, "Florence")
+// renders as /Italy/+/Florence
+const AnyLevel = "+"
+
+// ScopePath is a scope of the entities, as used by the scopeQ parameter
+// of entity queries and subscriptions
+type ScopePath struct {
+	levels []string
+}
+
+// Scope matches the entities in the scope made of the levels, e.g. Scope("Italy", "Tuscany")
+// renders as /Italy/Tuscany
+func Scope(levels ...string) ScopePath {
+	return ScopePath{
+		levels: levels,
+	}
+}
+
+func (s ScopePath) String() string {
+	return "/" + strings.Join(s.levels, "/")
+}
+
+// Descendants matches the entities in the scope or in any scope below it,
+// e.g. Scope("Italy").Descendants() renders as /Italy/#
+func (s ScopePath) Descendants() Term {
+	if len(s.levels) == 0 {
+		return Raw("/#")
+	}
+	return Raw(s.String() + "/#")
+}
+
+// AnyScope matches the entities having a scope
+func AnyScope() Term {
+	return Scope().Descendants()
+}